@@ -0,0 +1,45 @@
+package cancel
+
+import (
+	"context"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// New returns a new cancel command.
+func New(c *cli.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cancel <id>",
+		Short: "Cancel a deployment that is in progress",
+		Example: heredoc.Doc(`
+			airplane deployments cancel <id>
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(cmd.Root().Context(), c.Client, args[0])
+		},
+	}
+	return cmd
+}
+
+// Run runs the cancel command.
+func run(ctx context.Context, client api.APIClient, id string) error {
+	deployment, err := client.GetDeployment(ctx, id)
+	if err != nil {
+		return err
+	}
+	if deployment.Done() {
+		return errors.Errorf("deployment %s has already finished: %s", id, deployment.Status())
+	}
+
+	if err := client.CancelDeployment(ctx, api.CancelDeploymentRequest{ID: id}); err != nil {
+		return errors.Wrap(err, "cancelling deployment")
+	}
+	logger.Log("Cancelled deployment %s", logger.Bold(id))
+	return nil
+}
@@ -0,0 +1,37 @@
+package cancel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingClient struct {
+	*api.MockClient
+	cancelled []string
+}
+
+func (c *recordingClient) CancelDeployment(ctx context.Context, req api.CancelDeploymentRequest) error {
+	c.cancelled = append(c.cancelled, req.ID)
+	return nil
+}
+
+func TestRun(t *testing.T) {
+	require := require.New(t)
+	client := &recordingClient{MockClient: &api.MockClient{
+		Deployments: []api.Deployment{
+			{ID: "active"},
+			{ID: "done", SucceededAt: &time.Time{}},
+		},
+	}}
+
+	require.NoError(run(context.Background(), client, "active"))
+	require.Equal([]string{"active"}, client.cancelled)
+
+	err := run(context.Background(), client, "done")
+	require.EqualError(err, "deployment done has already finished: Succeeded")
+	require.Equal([]string{"active"}, client.cancelled)
+}
@@ -0,0 +1,39 @@
+package deployments
+
+import (
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/cmd/airplane/auth/login"
+	"github.com/airplanedev/cli/cmd/airplane/deployments/cancel"
+	"github.com/airplanedev/cli/cmd/airplane/deployments/get"
+	"github.com/airplanedev/cli/cmd/airplane/deployments/list"
+	"github.com/airplanedev/cli/cmd/airplane/deployments/logs"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// New returns a new cobra command.
+func New(c *cli.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "deployments",
+		Short:   "Manage deployments",
+		Long:    "Manage deployments",
+		Aliases: []string{"deployment"},
+		Example: heredoc.Doc(`
+			airplane deployments list
+			airplane deployments get <id>
+			airplane deployments logs <id> --follow
+			airplane deployments cancel <id>
+		`),
+		PersistentPreRunE: utils.WithParentPersistentPreRunE(func(cmd *cobra.Command, args []string) error {
+			return login.EnsureLoggedIn(cmd.Root().Context(), c)
+		}),
+	}
+
+	cmd.AddCommand(list.New(c))
+	cmd.AddCommand(get.New(c))
+	cmd.AddCommand(logs.New(c))
+	cmd.AddCommand(cancel.New(c))
+
+	return cmd
+}
@@ -0,0 +1,39 @@
+package get
+
+import (
+	"context"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/print"
+	"github.com/spf13/cobra"
+)
+
+// New returns a new get command.
+func New(c *cli.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get <id>",
+		Short: "Get information about a deployment",
+		Example: heredoc.Doc(`
+			airplane deployments get <id>
+			airplane deployments get <id> -o json
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(cmd.Root().Context(), c.Client, args[0])
+		},
+	}
+	return cmd
+}
+
+// Run runs the get command.
+func run(ctx context.Context, client api.APIClient, id string) error {
+	deployment, err := client.GetDeployment(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	print.Deployment(deployment)
+	return nil
+}
@@ -0,0 +1,27 @@
+package get
+
+import (
+	"context"
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type failingClient struct {
+	*api.MockClient
+}
+
+func (failingClient) GetDeployment(ctx context.Context, id string) (api.Deployment, error) {
+	return api.Deployment{}, errors.Errorf("deployment %s not found", id)
+}
+
+func TestRun(t *testing.T) {
+	require := require.New(t)
+
+	client := &api.MockClient{Deployments: []api.Deployment{{ID: "dep1"}}}
+	require.NoError(run(context.Background(), client, "dep1"))
+
+	require.EqualError(run(context.Background(), failingClient{client}, "dep2"), "deployment dep2 not found")
+}
@@ -0,0 +1,55 @@
+package list
+
+import (
+	"context"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/print"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	client api.APIClient
+
+	limit   int
+	envSlug string
+}
+
+// New returns a new list command.
+func New(c *cli.Config) *cobra.Command {
+	cfg := config{client: c.Client}
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists recent deployments",
+		Example: heredoc.Doc(`
+			airplane deployments list
+			airplane deployments list --env prod -o json
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cmd.Flags().IntVar(&cfg.limit, "limit", 20, "If >0, returns at most --limit items.")
+	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment to query. Defaults to your team's default environment.")
+
+	return cmd
+}
+
+// Run runs the list command.
+func run(ctx context.Context, cfg config) error {
+	resp, err := cfg.client.ListDeployments(ctx, api.ListDeploymentsRequest{
+		EnvSlug: cfg.envSlug,
+		Limit:   cfg.limit,
+	})
+	if err != nil {
+		return errors.Wrap(err, "list deployments")
+	}
+
+	print.Deployments(resp.Deployments)
+	return nil
+}
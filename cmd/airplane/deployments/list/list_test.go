@@ -0,0 +1,29 @@
+package list
+
+import (
+	"context"
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingClient struct {
+	*api.MockClient
+	req api.ListDeploymentsRequest
+}
+
+func (c *recordingClient) ListDeployments(ctx context.Context, req api.ListDeploymentsRequest) (api.ListDeploymentsResponse, error) {
+	c.req = req
+	return c.MockClient.ListDeployments(ctx, req)
+}
+
+func TestRun(t *testing.T) {
+	require := require.New(t)
+	client := &recordingClient{MockClient: &api.MockClient{
+		Deployments: []api.Deployment{{ID: "dep1"}, {ID: "dep2"}},
+	}}
+
+	require.NoError(run(context.Background(), config{client: client, limit: 5, envSlug: "prod"}))
+	require.Equal(api.ListDeploymentsRequest{EnvSlug: "prod", Limit: 5}, client.req)
+}
@@ -0,0 +1,108 @@
+package logs
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	client api.APIClient
+
+	id     string
+	follow bool
+}
+
+// New returns a new logs command.
+func New(c *cli.Config) *cobra.Command {
+	cfg := config{client: c.Client}
+
+	cmd := &cobra.Command{
+		Use:   "logs <id>",
+		Short: "Print the logs of a deployment",
+		Example: heredoc.Doc(`
+			airplane deployments logs <id>
+			airplane deployments logs <id> --follow
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.id = args[0]
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&cfg.follow, "follow", "f", false, "Keep printing logs until the deployment finishes.")
+
+	return cmd
+}
+
+// Run runs the logs command.
+func run(ctx context.Context, cfg config) error {
+	client := cfg.client
+
+	var prevToken string
+	for {
+		// Check whether the deployment has finished before fetching logs, so that no logs are
+		// missed between the last fetch and the deployment finishing.
+		deployment, err := client.GetDeployment(ctx, cfg.id)
+		if err != nil {
+			return errors.Wrap(err, "getting deployment")
+		}
+
+		// Page through the logs until we've caught up.
+		for {
+			r, err := client.GetDeploymentLogs(ctx, cfg.id, prevToken)
+			if err != nil {
+				return errors.Wrap(err, "getting deployment logs")
+			}
+			if len(r.Logs) == 0 {
+				break
+			}
+
+			api.SortLogs(r.Logs)
+			for _, l := range r.Logs {
+				printLog(l)
+			}
+			// Stop paging if the token did not advance, or the same page would be fetched forever.
+			if r.PrevPageToken == prevToken {
+				break
+			}
+			prevToken = r.PrevPageToken
+		}
+
+		if !cfg.follow || deployment.Done() {
+			if cfg.follow {
+				logger.Log("Deployment %s", deployment.Status())
+			}
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func printLog(l api.LogItem) {
+	text := l.Text
+	if strings.HasPrefix(text, "[builder] ") {
+		text = logger.Gray("%s", strings.TrimPrefix(text, "[builder] "))
+	}
+	prefix := "[" + logger.Yellow("deploy") + "] "
+	if l.TaskSlug != "" {
+		prefix = "[" + logger.Yellow("deploy") + " " + l.TaskSlug + "] "
+	}
+	if l.Level == api.LogLevelDebug {
+		prefix += "[" + logger.Blue("debug") + "] "
+	}
+	logger.Log("%s%s", prefix, text)
+}
@@ -0,0 +1,70 @@
+package logs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/stretchr/testify/require"
+)
+
+// pagedClient returns the page of logs keyed by the token it is called with.
+type pagedClient struct {
+	*api.MockClient
+	pages  map[string]api.GetDeploymentLogsResponse
+	tokens []string
+}
+
+func (c *pagedClient) GetDeploymentLogs(ctx context.Context, id string, prevToken string) (api.GetDeploymentLogsResponse, error) {
+	c.tokens = append(c.tokens, prevToken)
+	return c.pages[prevToken], nil
+}
+
+func TestRun(t *testing.T) {
+	log := func(text string) []api.LogItem {
+		return []api.LogItem{{Text: text, Level: api.LogLevelInfo}}
+	}
+
+	for _, test := range []struct {
+		name   string
+		pages  map[string]api.GetDeploymentLogsResponse
+		tokens []string
+	}{
+		{
+			name: "pages until there are no more logs",
+			pages: map[string]api.GetDeploymentLogsResponse{
+				"":   {Logs: log("one"), PrevPageToken: "t1"},
+				"t1": {Logs: log("two"), PrevPageToken: "t2"},
+			},
+			tokens: []string{"", "t1", "t2"},
+		},
+		{
+			name: "stops when the token does not advance",
+			pages: map[string]api.GetDeploymentLogsResponse{
+				"":   {Logs: log("one"), PrevPageToken: "t1"},
+				"t1": {Logs: log("two"), PrevPageToken: "t1"},
+			},
+			tokens: []string{"", "t1"},
+		},
+		{
+			name: "stops without a token",
+			pages: map[string]api.GetDeploymentLogsResponse{
+				"": {Logs: log("one")},
+			},
+			tokens: []string{""},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+			client := &pagedClient{
+				MockClient: &api.MockClient{
+					GetDeploymentResponse: &api.Deployment{ID: "dep1", SucceededAt: &time.Time{}},
+				},
+				pages: test.pages,
+			}
+			require.NoError(run(context.Background(), config{client: client, id: "dep1", follow: true}))
+			require.Equal(test.tokens, client.tokens)
+		})
+	}
+}
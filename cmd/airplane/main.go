@@ -30,6 +30,12 @@ func main() {
 			return
 		}
 
+		var exitErr utils.ExitCodeError
+		if errors.As(err, &exitErr) && exitErr.Msg == "" {
+			analytics.Close()
+			os.Exit(exitErr.Code)
+		}
+
 		logger.Debug("Error: %+v", err)
 		logger.Log("")
 		if exerr, ok := errors.Cause(err).(utils.ErrorExplained); ok {
@@ -44,6 +50,9 @@ func main() {
 		analytics.ReportError(err)
 
 		analytics.Close()
+		if exitErr.Code != 0 {
+			os.Exit(exitErr.Code)
+		}
 		os.Exit(1)
 	}
 }
@@ -17,7 +17,9 @@ import (
 	"github.com/airplanedev/cli/pkg/analytics"
 	"github.com/airplanedev/cli/pkg/api"
 	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/conf"
 	"github.com/airplanedev/cli/pkg/logger"
+	localtemplates "github.com/airplanedev/cli/pkg/templates"
 	"github.com/airplanedev/cli/pkg/utils"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -28,6 +30,9 @@ type config struct {
 	client      *api.Client
 	template    string
 	resetDemoDB bool
+
+	templatesDir string
+	vars         map[string]string
 }
 
 func New(c *cli.Config) *cobra.Command {
@@ -39,8 +44,16 @@ func New(c *cli.Config) *cobra.Command {
 		Example: heredoc.Doc(`
 			$ airplane init --template getting_started
 			$ airplane init --template github.com/airplanedev/templates/getting_started
+			$ airplane init --template my_starter_kit --var service=billing
+			$ airplane init --template ./starter-kits/service.tar.gz
 		`),
 		PersistentPreRunE: utils.WithParentPersistentPreRunE(func(cmd *cobra.Command, args []string) error {
+			// Local templates can be initialized offline.
+			if cfg.template != "" && !cfg.resetDemoDB {
+				if _, ok, err := localtemplates.Find(cfg.templatesDir, cfg.template); err == nil && ok {
+					return nil
+				}
+			}
 			return login.EnsureLoggedIn(cmd.Root().Context(), c)
 		}),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -49,7 +62,9 @@ func New(c *cli.Config) *cobra.Command {
 		Hidden: true,
 	}
 
-	cmd.Flags().StringVarP(&cfg.template, "template", "t", "", "Path of a template to initialize from in the format github.com/org/repo/path/to/template or path/to/template (in the airplanedev/templates repository)")
+	cmd.Flags().StringVarP(&cfg.template, "template", "t", "", "Template to initialize from: the slug of a local template, the path of a local directory or tarball, github.com/org/repo/path/to/template, or path/to/template (in the airplanedev/templates repository)")
+	cmd.Flags().StringVar(&cfg.templatesDir, "templates-dir", conf.GetTemplatesDir(), "The directory of local templates.")
+	cmd.Flags().StringToStringVar(&cfg.vars, "var", nil, "A value for a variable of a local template, as <name>=<value>. Can be repeated.")
 	cmd.Flags().BoolVar(&cfg.resetDemoDB, "reset-demo-db", false, "Resets the SQL DB resource [Demo DB] to its original state")
 
 	return cmd
@@ -85,6 +100,12 @@ func run(ctx context.Context, cfg config) error {
 			return utils.CopyFromGithubPath(cfg.template)
 		}
 
+		if t, ok, err := localtemplates.Find(cfg.templatesDir, cfg.template); err != nil {
+			return err
+		} else if ok {
+			return initFromLocalTemplate(cfg, t)
+		}
+
 		templates, err := ListTemplates(ctx)
 		if err != nil {
 			return err
@@ -176,6 +197,71 @@ func initFromTemplate(ctx context.Context, cfg config, templates []Template, git
 	return utils.CopyFromGithubPath(template.GitHubPath)
 }
 
+// initFromLocalTemplate copies a local template into a new directory named after it,
+// substituting the values of its variables.
+func initFromLocalTemplate(cfg config, t localtemplates.Template) error {
+	analytics.Track(cfg.root, "Template Cloned", map[string]interface{}{
+		"template_slug": t.Slug,
+		"local":         true,
+	})
+
+	var prompt func(localtemplates.Variable) (string, error)
+	if utils.CanPrompt() {
+		prompt = promptForVariable
+	}
+	values, err := localtemplates.ResolveValues(t.Variables, cfg.vars, prompt)
+	if err != nil {
+		return err
+	}
+
+	directory := t.Slug
+	if err := localtemplates.CheckDestination(t, directory); err != nil {
+		return err
+	}
+
+	src, closer, err := localtemplates.Open(t.Path)
+	if err != nil {
+		return err
+	}
+	defer closer.Close()
+
+	if err := utils.CreateDirectory(directory); err != nil {
+		return err
+	}
+	created, err := localtemplates.Render(src, directory, values)
+	if err != nil {
+		return err
+	}
+	for _, path := range created {
+		logger.Step("Copied %s", path)
+	}
+	// Local templates can be initialized offline, so their dependencies are not installed.
+	return utils.SetUpDirectory(directory, utils.SetUpDirectoryOpts{SkipInstall: true})
+}
+
+func promptForVariable(v localtemplates.Variable) (string, error) {
+	message := v.Description
+	if message == "" {
+		message = v.Name
+	}
+	var value string
+	opts := []survey.AskOpt{}
+	if v.Default == "" {
+		opts = append(opts, survey.WithValidator(survey.Required))
+	}
+	if err := survey.AskOne(
+		&survey.Input{
+			Message: message,
+			Default: v.Default,
+		},
+		&value,
+		opts...,
+	); err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
 const docsUrl = "http://docs.airplane.dev/templates/templates.json"
 const defaultGitPrefix = "github.com/airplanedev/templates"
 
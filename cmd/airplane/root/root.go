@@ -3,6 +3,7 @@ package root
 import (
 	"errors"
 	"os"
+	"strings"
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/airplanedev/cli/cmd/airplane/apikeys"
@@ -11,12 +12,14 @@ import (
 	"github.com/airplanedev/cli/cmd/airplane/auth/logout"
 	"github.com/airplanedev/cli/cmd/airplane/configs"
 	"github.com/airplanedev/cli/cmd/airplane/demo"
+	"github.com/airplanedev/cli/cmd/airplane/deployments"
 	"github.com/airplanedev/cli/cmd/airplane/root/initcmd"
 	"github.com/airplanedev/cli/cmd/airplane/runs"
 	"github.com/airplanedev/cli/cmd/airplane/tasks"
 	"github.com/airplanedev/cli/cmd/airplane/tasks/deploy"
 	"github.com/airplanedev/cli/cmd/airplane/tasks/dev"
 	"github.com/airplanedev/cli/cmd/airplane/tasks/execute"
+	"github.com/airplanedev/cli/cmd/airplane/templates"
 	"github.com/airplanedev/cli/cmd/airplane/version"
 	"github.com/airplanedev/cli/cmd/airplane/views"
 	"github.com/airplanedev/cli/pkg/analytics"
@@ -34,6 +37,7 @@ import (
 // New returns a new root cobra command.
 func New() *cobra.Command {
 	var output string
+	var columns []string
 	var cfg = &cli.Config{
 		Client: &api.Client{},
 	}
@@ -57,15 +61,34 @@ func New() *cobra.Command {
 				logger.Debug("error in analytics.Init: %v", err)
 			}
 
-			switch output {
-			case "json":
+			switch {
+			case output == "json":
 				print.DefaultFormatter = print.NewJSONFormatter()
-			case "yaml":
+			case output == "yaml":
 				print.DefaultFormatter = print.YAML{}
-			case "table":
-				print.DefaultFormatter = print.Table{}
+			case output == "table":
+				print.DefaultFormatter = print.Table{Columns: columns}
+			case output == "csv":
+				print.DefaultFormatter = print.NewCSVFormatter(columns)
+			case output == "jsonl":
+				print.DefaultFormatter = print.NewJSONLinesFormatter()
+			case strings.HasPrefix(output, "template="):
+				f, err := print.NewTemplateFormatter(strings.TrimPrefix(output, "template="))
+				if err != nil {
+					return err
+				}
+				print.DefaultFormatter = f
+			case strings.HasPrefix(output, "jsonpath="):
+				f, err := print.NewJSONPathFormatter(strings.TrimPrefix(output, "jsonpath="))
+				if err != nil {
+					return err
+				}
+				print.DefaultFormatter = f
 			default:
-				return errors.New("--output must be (json|yaml|table)")
+				return errors.New("--output must be (json|yaml|table|csv|jsonl|template=<template>|jsonpath=<expression>)")
+			}
+			if len(columns) > 0 && output != "table" && output != "csv" {
+				return errors.New("--columns can only be used with --output table or csv")
 			}
 
 			logger.EnableDebug = cfg.DebugMode
@@ -100,7 +123,9 @@ func New() *cobra.Command {
 	if !isatty.IsTerminal(os.Stdout.Fd()) {
 		defaultFormat = "json"
 	}
-	cmd.PersistentFlags().StringVarP(&output, "output", "o", defaultFormat, "The format to use for output (json|yaml|table).")
+	cmd.PersistentFlags().StringVarP(&output, "output", "o", defaultFormat, "The format to use for output (json|yaml|table|csv|jsonl|template=<template>|jsonpath=<expression>).")
+	cmd.PersistentFlags().StringSliceVar(&columns, "columns", nil, "Fields to include as columns of table and csv output, e.g. id,status,createdAt.")
+	cmd.PersistentFlags().StringVar(&print.SortBy, "sort-by", "", "Field to sort lists by, e.g. createdAt.")
 	cmd.PersistentFlags().BoolVar(&cfg.DebugMode, "debug", false, "Whether to produce debugging output.")
 	cmd.PersistentFlags().BoolVar(&cfg.Dev, "dev", false, "Dev mode: warning, not guaranteed to work and subject to change.")
 	if err := cmd.PersistentFlags().MarkHidden("dev"); err != nil {
@@ -123,7 +148,9 @@ func New() *cobra.Command {
 	cmd.AddCommand(auth.New(cfg))
 	cmd.AddCommand(configs.New(cfg))
 	cmd.AddCommand(demo.New(cfg))
+	cmd.AddCommand(deployments.New(cfg))
 	cmd.AddCommand(tasks.New(cfg))
+	cmd.AddCommand(templates.New(cfg))
 	cmd.AddCommand(views.New(cfg))
 	cmd.AddCommand(runs.New(cfg))
 	cmd.AddCommand(version.New(cfg))
@@ -0,0 +1,62 @@
+package outputs
+
+import (
+	"context"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/airplanedev/cli/pkg/print"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	root        *cli.Config
+	runID       string
+	outputsFile string
+}
+
+// New returns a new outputs command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg = config{root: c}
+
+	cmd := &cobra.Command{
+		Use:   "outputs <id>",
+		Short: "Get the outputs of a run",
+		Example: heredoc.Doc(`
+			airplane runs outputs <id>
+			airplane runs outputs <id> -o json
+			airplane runs outputs <id> --outputs-file outputs.csv
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.runID = args[0]
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.outputsFile, "outputs-file", "", "Write the outputs to a file instead. The format is chosen by the extension (.json, .yaml, .yml or .csv).")
+
+	return cmd
+}
+
+// Run runs the outputs command.
+func run(ctx context.Context, cfg config) error {
+	var client = cfg.root.Client
+
+	resp, err := client.GetOutputs(ctx, cfg.runID)
+	if err != nil {
+		return err
+	}
+
+	if cfg.outputsFile != "" {
+		if err := print.WriteOutputsFile(cfg.outputsFile, resp.Outputs); err != nil {
+			return err
+		}
+		logger.Log("Wrote outputs to %s", cfg.outputsFile)
+		return nil
+	}
+
+	print.Outputs(resp.Outputs, print.NewTruncation(false))
+	return nil
+}
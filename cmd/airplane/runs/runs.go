@@ -5,6 +5,8 @@ import (
 	"github.com/airplanedev/cli/cmd/airplane/auth/login"
 	"github.com/airplanedev/cli/cmd/airplane/runs/get"
 	"github.com/airplanedev/cli/cmd/airplane/runs/list"
+	"github.com/airplanedev/cli/cmd/airplane/runs/outputs"
+	"github.com/airplanedev/cli/cmd/airplane/runs/tree"
 	"github.com/airplanedev/cli/pkg/cli"
 	"github.com/airplanedev/cli/pkg/utils"
 	"github.com/spf13/cobra"
@@ -20,6 +22,8 @@ func New(c *cli.Config) *cobra.Command {
 		Example: heredoc.Doc(`
 			airplane runs list --task my-task
 			airplane runs get <id>
+			airplane runs outputs <id>
+			airplane runs tree <id>
 		`),
 		PersistentPreRunE: utils.WithParentPersistentPreRunE(func(cmd *cobra.Command, args []string) error {
 			return login.EnsureLoggedIn(cmd.Root().Context(), c)
@@ -28,6 +32,8 @@ func New(c *cli.Config) *cobra.Command {
 
 	cmd.AddCommand(list.New(c))
 	cmd.AddCommand(get.New(c))
+	cmd.AddCommand(outputs.New(c))
+	cmd.AddCommand(tree.New(c))
 
 	return cmd
 }
@@ -0,0 +1,233 @@
+package tree
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/airplanedev/cli/pkg/print"
+	"github.com/airplanedev/cli/pkg/server"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	root   *cli.Config
+	runID  string
+	local  bool
+	port   int
+	export string
+}
+
+// New returns a new tree command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg = config{root: c}
+
+	cmd := &cobra.Command{
+		Use:   "tree <id>",
+		Short: "Show a run and all of the runs it spawned",
+		Example: heredoc.Doc(`
+			airplane runs tree <id>
+			airplane runs tree <id> -o json
+			airplane runs tree <id> --local
+			airplane runs tree <id> --export mermaid
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.runID = args[0]
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cmd.Flags().BoolVar(&cfg.local, "local", false, "Fetch the run from a locally running dev server.")
+	cmd.Flags().IntVar(&cfg.port, "port", server.DefaultPort, "The port of the local dev server, used with --local.")
+	cmd.Flags().StringVar(&cfg.export, "export", "", "Export the tree as a diagram instead (mermaid|dot).")
+
+	return cmd
+}
+
+// runsClient is the subset of the API client needed to build a run tree.
+type runsClient interface {
+	GetRunDescendants(ctx context.Context, runID string) (api.GetRunDescendantsResponse, error)
+}
+
+// Node is a run along with the runs that it spawned.
+type Node struct {
+	Run      api.Run `json:"run" yaml:"run"`
+	Children []*Node `json:"children" yaml:"children"`
+}
+
+func run(ctx context.Context, cfg config) error {
+	var client = cfg.root.Client
+	var root api.Run
+	if cfg.local {
+		client = &api.Client{
+			Host:   fmt.Sprintf("127.0.0.1:%d", cfg.port),
+			Token:  cfg.root.Client.Token,
+			Source: cfg.root.Client.Source,
+			APIKey: cfg.root.Client.APIKey,
+			TeamID: cfg.root.Client.TeamID,
+		}
+		resp, err := client.GetInternalRun(ctx, cfg.runID)
+		if err != nil {
+			return errors.Wrap(err, "getting run from local dev server")
+		}
+		root = resp.Run
+	} else {
+		resp, err := client.GetRun(ctx, cfg.runID)
+		if err != nil {
+			return err
+		}
+		root = resp.Run
+	}
+
+	tree, err := buildTree(ctx, client, root)
+	if err != nil {
+		return err
+	}
+
+	switch cfg.export {
+	case "":
+		print.Print(tree, func() {
+			for _, line := range renderText(tree, time.Now()) {
+				fmt.Println(line)
+			}
+		})
+	case "mermaid":
+		fmt.Print(renderMermaid(tree))
+	case "dot":
+		fmt.Print(renderDOT(tree))
+	default:
+		return errors.New("--export must be (mermaid|dot)")
+	}
+	return nil
+}
+
+// buildTree recursively fetches the descendants of the given run.
+func buildTree(ctx context.Context, client runsClient, run api.Run) (*Node, error) {
+	node := &Node{Run: run, Children: []*Node{}}
+	resp, err := client.GetRunDescendants(ctx, run.RunID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting descendants of run %s", run.RunID)
+	}
+	for _, child := range resp.Descendants {
+		// Some implementations return every descendant rather than only direct children.
+		if child.ParentID != "" && child.ParentID != run.RunID {
+			continue
+		}
+		childNode, err := buildTree(ctx, client, child)
+		if err != nil {
+			return nil, err
+		}
+		node.Children = append(node.Children, childNode)
+	}
+	return node, nil
+}
+
+// renderText renders the tree as indented lines, e.g.
+//
+//	run123 my_workflow Succeeded (12s)
+//	├── run456 child_task Succeeded (3s)
+//	└── run789 other_task Failed (1s)
+func renderText(node *Node, now time.Time) []string {
+	lines := []string{describe(node.Run, now)}
+	return append(lines, renderChildren(node, "", now)...)
+}
+
+func renderChildren(node *Node, indent string, now time.Time) []string {
+	var lines []string
+	for i, child := range node.Children {
+		branch, nextIndent := "├── ", "│   "
+		if i == len(node.Children)-1 {
+			branch, nextIndent = "└── ", "    "
+		}
+		lines = append(lines, indent+branch+describe(child.Run, now))
+		lines = append(lines, renderChildren(child, indent+nextIndent, now)...)
+	}
+	return lines
+}
+
+func describe(run api.Run, now time.Time) string {
+	status := string(run.Status)
+	switch run.Status {
+	case api.RunSucceeded:
+		status = logger.Green(status)
+	case api.RunFailed, api.RunCancelled:
+		status = logger.Red(status)
+	default:
+		status = logger.Yellow(status)
+	}
+	return fmt.Sprintf("%s %s %s (%s)", run.RunID, taskLabel(run), status, duration(run, now))
+}
+
+func taskLabel(run api.Run) string {
+	if run.TaskName != "" {
+		return run.TaskName
+	}
+	return run.TaskID
+}
+
+// duration returns how long a run took, or how long it has been running for.
+func duration(run api.Run, now time.Time) time.Duration {
+	end := now
+	switch {
+	case run.SucceededAt != nil:
+		end = *run.SucceededAt
+	case run.FailedAt != nil:
+		end = *run.FailedAt
+	case run.CancelledAt != nil:
+		end = *run.CancelledAt
+	}
+	if run.CreatedAt.IsZero() || end.Before(run.CreatedAt) {
+		return 0
+	}
+	return end.Sub(run.CreatedAt).Round(time.Second)
+}
+
+// renderMermaid renders the tree as a Mermaid flowchart.
+func renderMermaid(tree *Node) string {
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+	walk(tree, func(parent, node *Node) {
+		fmt.Fprintf(&b, "  %s[\"%s<br/>%s\"]\n", node.Run.RunID, escape(taskLabel(node.Run)), node.Run.Status)
+		if parent != nil {
+			fmt.Fprintf(&b, "  %s --> %s\n", parent.Run.RunID, node.Run.RunID)
+		}
+	})
+	return b.String()
+}
+
+// renderDOT renders the tree as a Graphviz DOT digraph.
+func renderDOT(tree *Node) string {
+	var b strings.Builder
+	b.WriteString("digraph runs {\n")
+	walk(tree, func(parent, node *Node) {
+		fmt.Fprintf(&b, "  %q [label=\"%s\\n%s\"];\n", node.Run.RunID, escape(taskLabel(node.Run)), node.Run.Status)
+		if parent != nil {
+			fmt.Fprintf(&b, "  %q -> %q;\n", parent.Run.RunID, node.Run.RunID)
+		}
+	})
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// walk visits every node in the tree in depth-first order.
+func walk(node *Node, f func(parent, node *Node)) {
+	var visit func(parent, node *Node)
+	visit = func(parent, node *Node) {
+		f(parent, node)
+		for _, child := range node.Children {
+			visit(node, child)
+		}
+	}
+	visit(nil, node)
+}
+
+func escape(s string) string {
+	return strings.ReplaceAll(s, `"`, `'`)
+}
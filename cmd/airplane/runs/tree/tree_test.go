@@ -0,0 +1,76 @@
+package tree
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRunsClient struct {
+	descendants map[string][]api.Run
+}
+
+func (mc mockRunsClient) GetRunDescendants(ctx context.Context, runID string) (api.GetRunDescendantsResponse, error) {
+	return api.GetRunDescendantsResponse{Descendants: mc.descendants[runID]}, nil
+}
+
+func TestRunTree(t *testing.T) {
+	require := require.New(t)
+	color.NoColor = true
+
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		t := start.Add(d)
+		return &t
+	}
+	client := mockRunsClient{descendants: map[string][]api.Run{
+		"run1": {
+			{RunID: "run2", ParentID: "run1", TaskName: "child", Status: api.RunSucceeded, CreatedAt: start, SucceededAt: at(3 * time.Second)},
+			{RunID: "run3", ParentID: "run1", TaskName: "other", Status: api.RunFailed, CreatedAt: start, FailedAt: at(time.Second)},
+		},
+		"run2": {
+			{RunID: "run4", ParentID: "run2", TaskName: "grandchild", Status: api.RunActive, CreatedAt: start},
+		},
+	}}
+
+	tree, err := buildTree(context.Background(), client, api.Run{
+		RunID:       "run1",
+		TaskName:    "workflow",
+		Status:      api.RunSucceeded,
+		CreatedAt:   start,
+		SucceededAt: at(12 * time.Second),
+	})
+	require.NoError(err)
+
+	require.Equal([]string{
+		"run1 workflow Succeeded (12s)",
+		"├── run2 child Succeeded (3s)",
+		"│   └── run4 grandchild Active (5s)",
+		"└── run3 other Failed (1s)",
+	}, renderText(tree, start.Add(5*time.Second)))
+
+	require.Equal(`flowchart TD
+  run1["workflow<br/>Succeeded"]
+  run2["child<br/>Succeeded"]
+  run1 --> run2
+  run4["grandchild<br/>Active"]
+  run2 --> run4
+  run3["other<br/>Failed"]
+  run1 --> run3
+`, renderMermaid(tree))
+
+	require.Equal(`digraph runs {
+  "run1" [label="workflow\nSucceeded"];
+  "run2" [label="child\nSucceeded"];
+  "run1" -> "run2";
+  "run4" [label="grandchild\nActive"];
+  "run2" -> "run4";
+  "run3" [label="other\nFailed"];
+  "run1" -> "run3";
+}
+`, renderDOT(tree))
+}
@@ -0,0 +1,141 @@
+package codegen
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/codegen"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/airplanedev/lib/pkg/build"
+	"github.com/airplanedev/lib/pkg/deploy/discover"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	root   *cli.Config
+	client api.APIClient
+
+	paths []string
+	outs  []string
+}
+
+// New returns a new codegen command.
+func New(c *cli.Config) *cobra.Command {
+	cfg := config{
+		root:   c,
+		client: c.Client,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "codegen [paths...]",
+		Short: "Generate types for the parameters of tasks",
+		Long: heredoc.Doc(`
+			Generate a TypeScript interface or Python TypedDict for the parameters of each task in
+			the given paths, and a typed function that executes each task by slug.
+
+			Outputs are not typed: task definitions do not declare the shape of their outputs, so
+			the generated functions return the run as ` + "`airplane.execute`" + ` does.
+
+			By default, ` + codegen.TypeScriptFile + ` is generated if there are Node tasks and
+			` + codegen.PythonFile + ` if there are Python tasks, in the first path. These files are
+			regenerated whenever ` + "`airplane dev`" + ` runs a task in the same directory or one of
+			its subdirectories.
+		`),
+		Example: heredoc.Doc(`
+			airplane tasks codegen
+			airplane tasks codegen my-directory
+			airplane tasks codegen --out src/airplane.gen.ts --out scripts/airplane_gen.py
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.paths = args
+			if len(cfg.paths) == 0 {
+				cfg.paths = []string{"."}
+			}
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&cfg.outs, "out", nil, "A file to generate, in TypeScript (.ts) or Python (.py). Can be repeated.")
+
+	return cmd
+}
+
+func run(ctx context.Context, cfg config) error {
+	l := logger.NewStdErrLogger(logger.StdErrLoggerOpts{})
+
+	for _, out := range cfg.outs {
+		if _, err := codegen.LanguageFromPath(out); err != nil {
+			return err
+		}
+	}
+
+	d := &discover.Discoverer{
+		TaskDiscoverers: []discover.TaskDiscoverer{
+			&discover.DefnDiscoverer{
+				Client:             cfg.client,
+				Logger:             l,
+				MissingTaskHandler: codegen.IncludeMissingTask,
+			},
+			&discover.CodeTaskDiscoverer{
+				Client:             cfg.client,
+				Logger:             l,
+				MissingTaskHandler: codegen.IncludeMissingTask,
+			},
+		},
+		Client: cfg.client,
+		Logger: l,
+	}
+	taskConfigs, _, err := d.Discover(ctx, cfg.paths...)
+	if err != nil {
+		return err
+	}
+
+	outs := cfg.outs
+	if len(outs) == 0 {
+		if outs, err = defaultOuts(cfg.paths[0], taskConfigs); err != nil {
+			return err
+		}
+	}
+
+	tasks := codegen.NewTasks(taskConfigs)
+	for _, out := range outs {
+		if err := codegen.Write(out, tasks); err != nil {
+			return err
+		}
+		l.Step("Generated %s for %d task(s)", out, len(tasks))
+	}
+	return nil
+}
+
+// defaultOuts returns the files to generate in dir for the languages of the tasks.
+func defaultOuts(dir string, taskConfigs []discover.TaskConfig) ([]string, error) {
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+	var hasNode, hasPython bool
+	for _, tc := range taskConfigs {
+		kind, _, err := tc.Def.GetKindAndOptions()
+		if err != nil {
+			return nil, err
+		}
+		hasNode = hasNode || kind == build.TaskKindNode
+		hasPython = hasPython || kind == build.TaskKindPython
+	}
+
+	var outs []string
+	if hasNode {
+		outs = append(outs, filepath.Join(dir, codegen.TypeScriptFile))
+	}
+	if hasPython {
+		outs = append(outs, filepath.Join(dir, codegen.PythonFile))
+	}
+	if len(outs) == 0 {
+		return nil, errors.New("No Node or Python tasks found: use --out to choose the file to generate")
+	}
+	return outs, nil
+}
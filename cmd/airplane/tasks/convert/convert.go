@@ -0,0 +1,273 @@
+package convert
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/cmd/airplane/tasks/deploy"
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/airplanedev/cli/pkg/utils"
+	libapi "github.com/airplanedev/lib/pkg/api"
+	"github.com/airplanedev/lib/pkg/build"
+	"github.com/airplanedev/lib/pkg/deploy/discover"
+	"github.com/airplanedev/lib/pkg/deploy/taskdir"
+	"github.com/airplanedev/lib/pkg/deploy/taskdir/definitions"
+	"github.com/airplanedev/lib/pkg/runtime"
+	_ "github.com/airplanedev/lib/pkg/runtime/javascript"
+	_ "github.com/airplanedev/lib/pkg/runtime/typescript"
+	"github.com/airplanedev/lib/pkg/utils/fsx"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	formatInline = "inline"
+	formatYAML   = "yaml"
+)
+
+type config struct {
+	root   *cli.Config
+	client api.APIClient
+
+	path      string
+	to        string
+	removeOld bool
+	assumeYes bool
+	assumeNo  bool
+}
+
+// New returns a new convert command.
+func New(c *cli.Config) *cobra.Command {
+	cfg := config{
+		root:   c,
+		client: c.Client,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "convert <path> --to inline|yaml",
+		Short: "Convert a task between a definition file and inline config",
+		Long: heredoc.Doc(`
+			Rewrite the definition of a task from a *.task.yaml file into inline config in code,
+			or from inline config into a *.task.yaml file. Parameters, resources, env vars,
+			schedules and constraints are preserved.
+
+			Only the definition is converted: the code of the task has to be moved by hand, and
+			the command prints the steps to do so. Once the code has been moved and the old
+			entrypoint deleted, run the command again with --remove-old to delete the definition
+			file. Inline config is only supported for Node tasks.
+		`),
+		Example: heredoc.Doc(`
+			airplane tasks convert ./my_task.task.yaml --to inline
+			# After moving the code of my_task.ts into my_task.airplane.ts and deleting my_task.ts:
+			airplane tasks convert ./my_task.task.yaml --to inline --remove-old
+			airplane tasks convert ./my_task.airplane.ts --to yaml
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.path = args[0]
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.to, "to", "", "The form to convert the task to: inline or yaml.")
+	cmd.Flags().BoolVar(&cfg.removeOld, "remove-old", false, "Remove the definition file of a task whose code has been moved into its inline config.")
+	cmd.Flags().BoolVarP(&cfg.assumeYes, "yes", "y", false, "True to specify automatic yes to prompts.")
+	cmd.Flags().BoolVarP(&cfg.assumeNo, "no", "n", false, "True to specify automatic no to prompts.")
+
+	if err := cmd.MarkFlagRequired("to"); err != nil {
+		logger.Debug("error: %s", err)
+	}
+	if err := cmd.Flags().MarkHidden("yes"); err != nil {
+		logger.Debug("error: %s", err)
+	}
+	if err := cmd.Flags().MarkHidden("no"); err != nil {
+		logger.Debug("error: %s", err)
+	}
+
+	return cmd
+}
+
+func run(ctx context.Context, cfg config) error {
+	if cfg.assumeYes && cfg.assumeNo {
+		return errors.New("Cannot specify both --yes and --no")
+	}
+	l := logger.NewStdErrLogger(logger.StdErrLoggerOpts{})
+
+	switch cfg.to {
+	case formatInline:
+		return convertToInline(cfg, l)
+	case formatYAML:
+		if cfg.removeOld {
+			return errors.New("--remove-old cannot be used with --to yaml: the inline config is part of the task's code")
+		}
+		return convertToYAML(ctx, cfg, l)
+	default:
+		return errors.Errorf("Unknown --to %q: expected %s or %s", cfg.to, formatInline, formatYAML)
+	}
+}
+
+// convertToInline writes the definition file at cfg.path as inline config in a new
+// <entrypoint>.airplane.<ext> file.
+func convertToInline(cfg config, l logger.Logger) error {
+	if !definitions.IsTaskDef(cfg.path) {
+		return errors.Errorf("%s is not a task definition file", cfg.path)
+	}
+	dir, err := taskdir.Open(cfg.path)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+
+	def, err := dir.ReadDefinition()
+	if err != nil {
+		return err
+	}
+	kind, err := def.Kind()
+	if err != nil {
+		return err
+	}
+	if kind != build.TaskKindNode {
+		return errors.New("Inline config is only supported for Node tasks.")
+	}
+	entrypoint, err := def.GetAbsoluteEntrypoint()
+	if err != nil {
+		return err
+	}
+
+	inlinePath := inlineEntrypoint(entrypoint)
+	defnPath := dir.DefinitionPath()
+	if cfg.removeOld {
+		return removeDefinition(defnPath, entrypoint, inlinePath, l)
+	}
+
+	if ok, err := confirmOverwrite(cfg, inlinePath); err != nil || !ok {
+		return err
+	}
+	r, err := runtime.Lookup(inlinePath, kind)
+	if err != nil {
+		return errors.Wrapf(err, "unable to convert %q - check that your CLI is up to date", cfg.path)
+	}
+	code, fileMode, err := r.GenerateInline(&def)
+	if err != nil {
+		return errors.Wrap(err, "generating inline config")
+	}
+	if err := os.WriteFile(inlinePath, code, fileMode); err != nil {
+		return errors.Wrapf(err, "writing %s", inlinePath)
+	}
+	l.Step("Created %s", deploy.RelPath(inlinePath))
+
+	l.SuggestSteps("To finish converting the task:",
+		fmt.Sprintf("Move the code of %s into the task function in %s, then delete %s.", deploy.RelPath(entrypoint), deploy.RelPath(inlinePath), deploy.RelPath(entrypoint)),
+		fmt.Sprintf("Run `airplane tasks convert %s --to inline --remove-old` to delete the definition file. Otherwise, %s is defined twice.", deploy.RelPath(defnPath), def.GetSlug()),
+	)
+	return nil
+}
+
+// removeDefinition removes the definition file of a task that has been converted to inline
+// config. It refuses to while the old entrypoint exists, since its code would no longer be
+// part of any task.
+func removeDefinition(defnPath, entrypoint, inlinePath string, l logger.Logger) error {
+	if entrypoint != inlinePath && fsx.Exists(entrypoint) {
+		return errors.Errorf("%s still exists: move its code into %s and delete it before removing %s", deploy.RelPath(entrypoint), deploy.RelPath(inlinePath), deploy.RelPath(defnPath))
+	}
+	if !fsx.Exists(inlinePath) {
+		return errors.Errorf("%s does not exist: convert %s without --remove-old first", deploy.RelPath(inlinePath), deploy.RelPath(defnPath))
+	}
+	if err := os.Remove(defnPath); err != nil {
+		return errors.Wrapf(err, "removing %s", defnPath)
+	}
+	l.Step("Removed %s", deploy.RelPath(defnPath))
+	return nil
+}
+
+// convertToYAML writes the inline config of the tasks in the file at cfg.path into
+// <slug>.task.yaml files next to it.
+func convertToYAML(ctx context.Context, cfg config, l logger.LoggerWithLoader) error {
+	if definitions.IsTaskDef(cfg.path) {
+		return errors.Errorf("%s is already a task definition file", cfg.path)
+	}
+
+	// Tasks that have not been deployed yet can be converted too.
+	missingTaskHandler := func(ctx context.Context, def definitions.DefinitionInterface) (*libapi.TaskMetadata, error) {
+		return &libapi.TaskMetadata{Slug: def.GetSlug()}, nil
+	}
+	d := &discover.Discoverer{
+		TaskDiscoverers: []discover.TaskDiscoverer{
+			&discover.CodeTaskDiscoverer{
+				Client:             cfg.client,
+				Logger:             l,
+				MissingTaskHandler: missingTaskHandler,
+			},
+		},
+		Client: cfg.client,
+		Logger: l,
+	}
+	taskConfigs, _, err := d.Discover(ctx, cfg.path)
+	if err != nil {
+		return err
+	}
+	if len(taskConfigs) == 0 {
+		return errors.Errorf("No inline task config found in %s", cfg.path)
+	}
+
+	for _, tc := range taskConfigs {
+		def, ok := tc.Def.(*definitions.Definition_0_3)
+		if !ok {
+			return errors.Errorf("unable to convert %s: unsupported definition", tc.Def.GetSlug())
+		}
+
+		dir := filepath.Dir(tc.TaskEntrypoint)
+		defnPath := filepath.Join(dir, def.GetSlug()+".task.yaml")
+		if ok, err := confirmOverwrite(cfg, defnPath); err != nil {
+			return err
+		} else if !ok {
+			continue
+		}
+
+		// Entrypoints in definition files are relative to the definition file.
+		entrypoint, err := filepath.Rel(dir, tc.TaskEntrypoint)
+		if err != nil {
+			return errors.Wrap(err, "determining relative entrypoint")
+		}
+		if err := def.SetEntrypoint(entrypoint); err != nil {
+			return err
+		}
+		buf, err := def.GenerateCommentedFile(definitions.DefFormatYAML)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(defnPath, buf, 0644); err != nil {
+			return errors.Wrapf(err, "writing %s", defnPath)
+		}
+		l.Step("Created %s", deploy.RelPath(defnPath))
+		l.SuggestSteps("To finish converting the task:",
+			fmt.Sprintf("Replace the inline config of %s in %s with a default export of the task function. Otherwise, %s is defined twice.", def.GetSlug(), deploy.RelPath(tc.TaskEntrypoint), def.GetSlug()),
+		)
+	}
+	return nil
+}
+
+// inlineEntrypoint returns the file that inline config for the given entrypoint is written to,
+// e.g. my_task.airplane.ts for my_task.ts.
+func inlineEntrypoint(entrypoint string) string {
+	ext := filepath.Ext(entrypoint)
+	base := strings.TrimSuffix(entrypoint, ext)
+	if strings.HasSuffix(base, ".airplane") {
+		return entrypoint
+	}
+	return base + ".airplane" + ext
+}
+
+func confirmOverwrite(cfg config, path string) (bool, error) {
+	if !fsx.Exists(path) {
+		return true, nil
+	}
+	question := fmt.Sprintf("Would you like to overwrite %s?", deploy.RelPath(path))
+	return utils.ConfirmWithAssumptions(question, cfg.assumeYes, cfg.assumeNo)
+}
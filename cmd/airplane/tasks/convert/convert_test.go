@@ -0,0 +1,88 @@
+package convert
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/airplanedev/lib/pkg/deploy/taskdir"
+	"github.com/airplanedev/lib/pkg/deploy/taskdir/definitions"
+	"github.com/stretchr/testify/require"
+)
+
+// copyFixtures copies the fixtures into a temporary directory, since converting writes
+// next to the converted file.
+func copyFixtures(t *testing.T) string {
+	dir := t.TempDir()
+	for _, name := range []string{"my_task.task.yaml", "my_task.ts", "package.json"} {
+		buf, err := os.ReadFile(filepath.Join("fixtures", name))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), buf, 0644))
+	}
+	return dir
+}
+
+func readDefinition(t *testing.T, path string) definitions.Definition_0_3 {
+	dir, err := taskdir.Open(path)
+	require.NoError(t, err)
+	defer dir.Close()
+	def, err := dir.ReadDefinition()
+	require.NoError(t, err)
+	// The entrypoint moves to the inline file, everything else has to survive.
+	def.Node.Entrypoint = ""
+	return def
+}
+
+func TestConvertRoundTrip(t *testing.T) {
+	if _, err := exec.LookPath("node"); err != nil {
+		t.Skip("node is required to discover inline config")
+	}
+	require := require.New(t)
+	ctx := context.Background()
+	l := &logger.MockLogger{}
+
+	dir := copyFixtures(t)
+	defnPath := filepath.Join(dir, "my_task.task.yaml")
+	inlinePath := filepath.Join(dir, "my_task.airplane.ts")
+	before := readDefinition(t, defnPath)
+
+	cfg := config{client: &api.MockClient{}, path: defnPath, to: formatInline}
+	require.NoError(convertToInline(cfg, l))
+	require.FileExists(inlinePath)
+
+	cfg = config{client: &api.MockClient{}, path: inlinePath, to: formatYAML, assumeYes: true}
+	require.NoError(convertToYAML(ctx, cfg, l))
+
+	after := readDefinition(t, defnPath)
+	require.Equal(before, after)
+}
+
+func TestConvertRemoveOld(t *testing.T) {
+	require := require.New(t)
+	l := &logger.MockLogger{}
+
+	dir := copyFixtures(t)
+	defnPath := filepath.Join(dir, "my_task.task.yaml")
+	cfg := config{path: defnPath, to: formatInline, removeOld: true}
+
+	// The code has not been moved yet.
+	err := convertToInline(cfg, l)
+	require.Error(err)
+	require.Contains(err.Error(), "my_task.ts still exists")
+	require.FileExists(defnPath)
+
+	// The code has been moved, but the inline file was never generated.
+	require.NoError(os.Remove(filepath.Join(dir, "my_task.ts")))
+	err = convertToInline(cfg, l)
+	require.Error(err)
+	require.Contains(err.Error(), "my_task.airplane.ts does not exist")
+	require.FileExists(defnPath)
+
+	require.NoError(os.WriteFile(filepath.Join(dir, "my_task.airplane.ts"), []byte("// moved\n"), 0644))
+	require.NoError(convertToInline(cfg, l))
+	require.NoFileExists(defnPath)
+}
@@ -0,0 +1,3 @@
+export default async function (params) {
+  return params.user_id;
+}
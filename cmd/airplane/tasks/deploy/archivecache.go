@@ -0,0 +1,159 @@
+package deploy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/conf"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/pkg/errors"
+)
+
+// archiveCacheTTL is how long an upload is reused for. Uploads eventually expire, so older
+// entries are ignored and evicted.
+const archiveCacheTTL = 24 * time.Hour
+
+// archiveCache tracks which build roots were already uploaded, keyed by a hash of their
+// contents.
+type archiveCache struct {
+	path string
+	// namespace separates uploads made to different API hosts and teams.
+	namespace string
+
+	mu    sync.Mutex
+	cache conf.ArchiveCache
+	dirty bool
+
+	// skipped and savedBytes count the uploads that were skipped during this deploy.
+	skipped    int
+	savedBytes int
+}
+
+// archiveCacheNamespace returns the API host and team that uploads are made to, or an empty
+// string if the team is unknown.
+func archiveCacheNamespace(c *cli.Config) string {
+	if c == nil || c.Client == nil {
+		return ""
+	}
+	teamID := c.Client.TeamID
+	if teamID == "" {
+		teamID = c.ParseTokenForAnalytics().TeamID
+	}
+	if teamID == "" {
+		return ""
+	}
+	return c.Client.HostURL() + "/" + teamID
+}
+
+// newArchiveCache loads the archive cache at path. If the cache cannot be read, an empty
+// cache is used instead.
+func newArchiveCache(l logger.Logger, path, namespace string) *archiveCache {
+	cache, err := conf.ReadArchiveCache(path)
+	if err != nil {
+		l.Debug("Ignoring archive cache: %v", err)
+	}
+	return &archiveCache{
+		path:      path,
+		namespace: namespace,
+		cache:     cache,
+	}
+}
+
+// get returns the upload for the given hash, if it has not expired.
+func (c *archiveCache) get(hash string) (conf.CachedUpload, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	u, ok := c.cache.Uploads[hash]
+	if !ok || time.Since(u.CreatedAt) > archiveCacheTTL {
+		return conf.CachedUpload{}, false
+	}
+	c.skipped++
+	c.savedBytes += u.SizeBytes
+	return u, true
+}
+
+func (c *archiveCache) put(hash string, u conf.CachedUpload) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache.Uploads[hash] = u
+	c.dirty = true
+}
+
+// save evicts expired uploads and writes the cache, if it changed.
+func (c *archiveCache) save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for hash, u := range c.cache.Uploads {
+		if time.Since(u.CreatedAt) > archiveCacheTTL {
+			delete(c.cache.Uploads, hash)
+			c.dirty = true
+		}
+	}
+	if !c.dirty {
+		return nil
+	}
+	if err := conf.WriteArchiveCache(c.path, c.cache); err != nil {
+		return err
+	}
+	c.dirty = false
+	return nil
+}
+
+// hash returns a hash of the manifest of root: the path, mode, size and content hash of every
+// entry in its build archive, in order. Modification times and owners are left out, so that
+// checkouts of the same commit have the same hash.
+func (c *archiveCache) hash(root string) (string, error) {
+	h := sha256.New()
+	// Separate the namespace from the manifest, which starts with a file name.
+	if _, err := io.WriteString(h, c.namespace+"\x00"); err != nil {
+		return "", err
+	}
+	err := walkArchive(root, func(path, rel string, info fs.FileInfo) error {
+		var size int64
+		var content string
+		switch {
+		case info.IsDir():
+		case info.Mode()&fs.ModeSymlink != 0:
+			link, err := archiveLink(root, path)
+			if err != nil {
+				return err
+			}
+			content = link
+		case info.Mode().IsRegular():
+			sum, err := hashFile(path)
+			if err != nil {
+				return err
+			}
+			size, content = info.Size(), sum
+		default:
+			// Sockets, devices and other special files are not archived.
+			return nil
+		}
+		_, err := fmt.Fprintf(h, "%s\x00%o\x00%d\x00%s\n", rel, info.Mode(), size, content)
+		return err
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "hashing %s", root)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,92 @@
+package deploy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/conf"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveCache(t *testing.T) {
+	require := require.New(t)
+
+	root := t.TempDir()
+	require.NoError(os.MkdirAll(filepath.Join(root, "lib"), 0755))
+	require.NoError(os.WriteFile(filepath.Join(root, "index.ts"), []byte("export default 1"), 0644))
+	require.NoError(os.WriteFile(filepath.Join(root, "lib", "util.ts"), []byte("export const a = 1"), 0644))
+
+	path := filepath.Join(t.TempDir(), "archives.json")
+	cache := newArchiveCache(&logger.MockLogger{}, path, "api.airplane.dev/tea123")
+	hash, err := cache.hash(root)
+	require.NoError(err)
+
+	// Modification times do not change the hash.
+	later := time.Now().Add(time.Hour)
+	require.NoError(os.Chtimes(filepath.Join(root, "index.ts"), later, later))
+	h, err := cache.hash(root)
+	require.NoError(err)
+	require.Equal(hash, h)
+
+	// Neither does a .git directory.
+	require.NoError(os.MkdirAll(filepath.Join(root, ".git"), 0755))
+	require.NoError(os.WriteFile(filepath.Join(root, ".git", "HEAD"), []byte("ref: refs/heads/main"), 0644))
+	h, err = cache.hash(root)
+	require.NoError(err)
+	require.Equal(hash, h)
+
+	// Contents, modes and namespaces do.
+	other := newArchiveCache(&logger.MockLogger{}, path, "api.airplane.dev/tea456")
+	h, err = other.hash(root)
+	require.NoError(err)
+	require.NotEqual(hash, h)
+	require.NoError(os.Chmod(filepath.Join(root, "index.ts"), 0755))
+	h, err = cache.hash(root)
+	require.NoError(err)
+	require.NotEqual(hash, h)
+	require.NoError(os.Chmod(filepath.Join(root, "index.ts"), 0644))
+	require.NoError(os.WriteFile(filepath.Join(root, "lib", "util.ts"), []byte("export const a = 2"), 0644))
+	h, err = cache.hash(root)
+	require.NoError(err)
+	require.NotEqual(hash, h)
+
+	_, ok := cache.get(hash)
+	require.False(ok)
+	cache.put(hash, conf.CachedUpload{UploadID: "upl123", SizeBytes: 100, CreatedAt: time.Now()})
+	cache.put("expired", conf.CachedUpload{UploadID: "upl456", SizeBytes: 100, CreatedAt: time.Now().Add(-2 * archiveCacheTTL)})
+	require.NoError(cache.save())
+
+	// Uploads are reused across deploys, unless they have expired.
+	cache = newArchiveCache(&logger.MockLogger{}, path, "api.airplane.dev/tea123")
+	u, ok := cache.get(hash)
+	require.True(ok)
+	require.Equal("upl123", u.UploadID)
+	_, ok = cache.get("expired")
+	require.False(ok)
+	require.Equal(1, cache.skipped)
+	require.Equal(100, cache.savedBytes)
+}
+
+func TestArchiveCacheNamespace(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal("", archiveCacheNamespace(nil))
+	require.Equal("", archiveCacheNamespace(&cli.Config{Client: &api.Client{Host: "api.airplane.dev"}}))
+
+	c := &cli.Config{Client: &api.Client{Host: "api.airplane.dev", TeamID: "tea_api_key"}}
+	require.Equal("https://api.airplane.dev/tea_api_key", archiveCacheNamespace(c))
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"userID": "usr_1",
+		"teamID": "tea_token",
+	}).SignedString([]byte("secret"))
+	require.NoError(err)
+	c = &cli.Config{Client: &api.Client{Host: "api.airplane.dev", Token: token}}
+	require.Equal("https://api.airplane.dev/tea_token", archiveCacheNamespace(c))
+}
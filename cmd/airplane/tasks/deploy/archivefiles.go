@@ -0,0 +1,314 @@
+package deploy
+
+import (
+	"archive/tar"
+	"bufio"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/airplanedev/cli/pkg/print"
+	libBuild "github.com/airplanedev/lib/pkg/build"
+	"github.com/airplanedev/lib/pkg/deploy/discover"
+	"github.com/dustin/go-humanize"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/pkg/errors"
+)
+
+// airplaneIgnoreFile lists the paths in a build root, in gitignore syntax, that are left out of
+// its build archive.
+const airplaneIgnoreFile = ".airplaneignore"
+
+// archiveFile is a file that is included in a build archive.
+type archiveFile struct {
+	// Path is relative to the build root, separated by slashes.
+	Path string `json:"path" yaml:"path"`
+	Size int64  `json:"size" yaml:"size"`
+}
+
+// readAirplaneIgnore returns a matcher for the .airplaneignore file in root, or nil if there is
+// no such file.
+func readAirplaneIgnore(root string) (gitignore.Matcher, error) {
+	f, err := os.Open(filepath.Join(root, airplaneIgnoreFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", airplaneIgnoreFile)
+	}
+	defer f.Close()
+
+	var patterns []gitignore.Pattern
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+			continue
+		}
+		patterns = append(patterns, gitignore.ParsePattern(line, nil))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "reading %s", airplaneIgnoreFile)
+	}
+	return gitignore.NewMatcher(patterns), nil
+}
+
+// walkArchive calls fn, in lexical order, for every path in root that is included in its build
+// archive. rel is the path relative to root, separated by slashes.
+func walkArchive(root string, fn func(path, rel string, info fs.FileInfo) error) error {
+	matcher, err := readAirplaneIgnore(root)
+	if err != nil {
+		return err
+	}
+	return filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() && entry.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		if matcher != nil && matcher.Match(strings.Split(rel, "/"), entry.IsDir()) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		return fn(path, rel, info)
+	})
+}
+
+// listArchiveFiles returns the files that are included in the build archive of root.
+func listArchiveFiles(root string) ([]archiveFile, error) {
+	var files []archiveFile
+	err := walkArchive(root, func(path, rel string, info fs.FileInfo) error {
+		if info.Mode().IsRegular() {
+			files = append(files, archiveFile{Path: rel, Size: info.Size()})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "listing files in %s", root)
+	}
+	return files, nil
+}
+
+// writeArchive writes a tar archive of the files in the build archive of root to w. The archive
+// only depends on the names, modes and contents of the files: entries are sorted and
+// modification times and owners are zeroed.
+func writeArchive(w io.Writer, root string) error {
+	tw := tar.NewWriter(w)
+	err := walkArchive(root, func(path, rel string, info fs.FileInfo) error {
+		var link string
+		switch {
+		case info.Mode()&fs.ModeSymlink != 0:
+			var err error
+			if link, err = archiveLink(root, path); err != nil {
+				return err
+			}
+		case info.IsDir(), info.Mode().IsRegular():
+		default:
+			// Skip sockets, devices and other special files.
+			return nil
+		}
+
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = rel
+		hdr.Format = tar.FormatPAX
+		hdr.ModTime = time.Time{}
+		hdr.AccessTime = time.Time{}
+		hdr.ChangeTime = time.Time{}
+		hdr.Uid, hdr.Gid = 0, 0
+		hdr.Uname, hdr.Gname = "", ""
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return errors.Wrapf(err, "archiving %s", root)
+	}
+	return tw.Close()
+}
+
+// archiveLink returns the target of the symlink at path as it is archived. Absolute links are
+// kept as-is. Relative links that point outside of root would not resolve in the build archive,
+// so they are rejected.
+func archiveLink(root, path string) (string, error) {
+	link, err := os.Readlink(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "reading link %s", path)
+	}
+	if filepath.IsAbs(link) {
+		return link, nil
+	}
+	target := filepath.Join(filepath.Dir(path), link)
+	rel, err := filepath.Rel(root, target)
+	if err != nil {
+		return "", err
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", errors.Errorf("%s links to %s, which is outside of %s and would not resolve in the build archive: replace the link with a copy of its target or add it to %s",
+			RelPath(path), link, RelPath(root), airplaneIgnoreFile)
+	}
+	return link, nil
+}
+
+// maxOffendingPaths is the number of paths that are listed when a build archive is too large.
+const maxOffendingPaths = 10
+
+// checkArchiveSize returns an error listing the largest top-level paths in root if its files
+// add up to more than maxBytes.
+func checkArchiveSize(root string, files []archiveFile, maxBytes int64) error {
+	var total int64
+	sizes := map[string]int64{}
+	for _, f := range files {
+		total += f.Size
+		top := f.Path
+		if i := strings.Index(top, "/"); i >= 0 {
+			top = top[:i+1]
+		}
+		sizes[top] += f.Size
+	}
+	if total <= maxBytes {
+		return nil
+	}
+
+	paths := make([]string, 0, len(sizes))
+	for p := range sizes {
+		paths = append(paths, p)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if sizes[paths[i]] != sizes[paths[j]] {
+			return sizes[paths[i]] > sizes[paths[j]]
+		}
+		return paths[i] < paths[j]
+	})
+	if len(paths) > maxOffendingPaths {
+		paths = paths[:maxOffendingPaths]
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "build archive of %s is %s, which exceeds the limit of %s. Largest paths:\n",
+		RelPath(root), humanize.Bytes(uint64(total)), humanize.Bytes(uint64(maxBytes)))
+	for _, p := range paths {
+		fmt.Fprintf(&b, "  %8s  %s\n", humanize.Bytes(uint64(sizes[p])), p)
+	}
+	fmt.Fprintf(&b, "Add paths that are not needed to build your task to %s, or raise --max-archive-size.", airplaneIgnoreFile)
+	return errors.New(b.String())
+}
+
+// ArchiveInspection lists the files in the build archive of a build root.
+type ArchiveInspection struct {
+	Root string `json:"root" yaml:"root"`
+	// Slugs are the tasks and views that are built from the root.
+	Slugs      []string      `json:"slugs" yaml:"slugs"`
+	TotalBytes int64         `json:"totalBytes" yaml:"totalBytes"`
+	Files      []archiveFile `json:"files" yaml:"files"`
+}
+
+// inspectArchives prints the contents of the build archives that would be uploaded. If a size
+// budget is configured, an error is returned for the first archive that exceeds it.
+func (d *deployer) inspectArchives(taskConfigs []discover.TaskConfig, viewConfigs []discover.ViewConfig) error {
+	roots, slugs, err := getBuildRoots(taskConfigs, viewConfigs)
+	if err != nil {
+		return err
+	}
+
+	inspections := []ArchiveInspection{}
+	files := map[string][]archiveFile{}
+	for _, root := range roots {
+		rootFiles, err := listArchiveFiles(root)
+		if err != nil {
+			return err
+		}
+		files[root] = rootFiles
+		inspection := ArchiveInspection{
+			Root:  RelPath(root),
+			Slugs: slugs[root],
+			Files: rootFiles,
+		}
+		for _, f := range rootFiles {
+			inspection.TotalBytes += f.Size
+		}
+		inspections = append(inspections, inspection)
+	}
+
+	print.Print(inspections, func() {
+		for _, inspection := range inspections {
+			d.logger.Log("%s (%s): %s in %d %s", logger.Bold(inspection.Root), strings.Join(inspection.Slugs, ", "),
+				humanize.Bytes(uint64(inspection.TotalBytes)), len(inspection.Files), pluralize(len(inspection.Files), "file", "files"))
+			for _, f := range inspection.Files {
+				d.logger.Log("  %8s  %s", humanize.Bytes(uint64(f.Size)), f.Path)
+			}
+			d.logger.Log("")
+		}
+	})
+
+	if d.cfg.maxArchiveBytes > 0 {
+		for _, root := range roots {
+			if err := checkArchiveSize(root, files[root], d.cfg.maxArchiveBytes); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getBuildRoots returns the build roots of the tasks and views that need building, in order,
+// and the slugs of the tasks and views that are built from each root.
+func getBuildRoots(taskConfigs []discover.TaskConfig, viewConfigs []discover.ViewConfig) ([]string, map[string][]string, error) {
+	var roots []string
+	slugs := map[string][]string{}
+	add := func(root, slug string) {
+		if _, ok := slugs[root]; !ok {
+			roots = append(roots, root)
+		}
+		slugs[root] = append(slugs[root], slug)
+	}
+	for _, tc := range taskConfigs {
+		kind, _, err := tc.Def.GetKindAndOptions()
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok, err := libBuild.NeedsBuilding(kind); err != nil {
+			return nil, nil, err
+		} else if ok {
+			add(tc.TaskRoot, tc.Def.GetSlug())
+		}
+	}
+	for _, vc := range viewConfigs {
+		add(vc.Root, vc.Def.Slug)
+	}
+	return roots, slugs, nil
+}
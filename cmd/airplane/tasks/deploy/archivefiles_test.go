@@ -0,0 +1,131 @@
+package deploy
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListArchiveFiles(t *testing.T) {
+	require := require.New(t)
+
+	root := t.TempDir()
+	for path, content := range map[string]string{
+		".airplaneignore":         "# Dependencies\nnode_modules/\n.env*\n!.env.example\n/data\n",
+		".env":                    "SECRET=1",
+		".env.example":            "SECRET=",
+		"index.ts":                "export default 1",
+		"data/big.csv":            "a,b,c",
+		"lib/data/small.csv":      "a",
+		"node_modules/x/index.js": "module.exports = 1",
+		"lib/node_modules/y/y.js": "module.exports = 2",
+		".git/HEAD":               "ref: refs/heads/main",
+	} {
+		p := filepath.Join(root, filepath.FromSlash(path))
+		require.NoError(os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(os.WriteFile(p, []byte(content), 0644))
+	}
+
+	files, err := listArchiveFiles(root)
+	require.NoError(err)
+	var paths []string
+	for _, f := range files {
+		paths = append(paths, f.Path)
+	}
+	require.Equal([]string{".airplaneignore", ".env.example", "index.ts", "lib/data/small.csv"}, paths)
+
+	// The archive only has the files that are not ignored, and their directories.
+	entries, err := readArchive(root)
+	require.NoError(err)
+	var archived []string
+	for name := range entries {
+		archived = append(archived, name)
+	}
+	sort.Strings(archived)
+	require.Equal([]string{".airplaneignore", ".env.example", "index.ts", "lib", "lib/data", "lib/data/small.csv"}, archived)
+}
+
+// readArchive returns the contents of the regular files and the targets of the links in the
+// archive of root, by name. Directories are mapped to an empty string.
+func readArchive(root string) (map[string]string, error) {
+	var buf bytes.Buffer
+	if err := writeArchive(&buf, root); err != nil {
+		return nil, err
+	}
+	entries := map[string]string{}
+	tr := tar.NewReader(&buf)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return entries, nil
+		} else if err != nil {
+			return nil, err
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		entries[hdr.Name] = string(content) + hdr.Linkname
+	}
+}
+
+func TestWriteArchive(t *testing.T) {
+	require := require.New(t)
+
+	repo := t.TempDir()
+	root := filepath.Join(repo, "task")
+	for path, content := range map[string]string{
+		"shared/util.ts":               "export const util = 1",
+		"task/index.ts":                "export default 1",
+		"task/lib/helper.ts":           "export const helper = 1",
+		"task/node_modules/x/index.js": "module.exports = 1",
+	} {
+		p := filepath.Join(repo, filepath.FromSlash(path))
+		require.NoError(os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(os.WriteFile(p, []byte(content), 0644))
+	}
+	require.NoError(os.Symlink("lib/helper.ts", filepath.Join(root, "helper.ts")))
+
+	entries, err := readArchive(root)
+	require.NoError(err)
+	require.Equal("export default 1", entries["index.ts"])
+
+	// Dependencies are only left out by an .airplaneignore.
+	require.Equal("module.exports = 1", entries["node_modules/x/index.js"])
+
+	// Links within the root stay relative.
+	require.Equal("lib/helper.ts", entries["helper.ts"])
+
+	// Links outside of the root would dangle in the archive.
+	require.NoError(os.Symlink("../shared/util.ts", filepath.Join(root, "util.ts")))
+	_, err = readArchive(root)
+	require.ErrorContains(err, "util.ts links to ../shared/util.ts, which is outside of")
+}
+
+func TestCheckArchiveSize(t *testing.T) {
+	require := require.New(t)
+
+	files := []archiveFile{
+		{Path: "index.ts", Size: 100},
+		{Path: "node_modules/a/index.js", Size: 3000},
+		{Path: "node_modules/b/index.js", Size: 4000},
+		{Path: "data/big.csv", Size: 5000},
+	}
+	require.NoError(checkArchiveSize("/repo/task", files, 20000))
+
+	err := checkArchiveSize("/repo/task", files, 10000)
+	require.Error(err)
+	lines := strings.Split(err.Error(), "\n")
+	require.Contains(lines[0], "12 kB")
+	require.Contains(lines[0], "10 kB")
+	require.Contains(lines[1], "node_modules/")
+	require.Contains(lines[2], "data/")
+	require.Contains(lines[3], "index.ts")
+}
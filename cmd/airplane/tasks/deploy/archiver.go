@@ -0,0 +1,58 @@
+package deploy
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+
+	"github.com/airplanedev/cli/pkg/api"
+	libapi "github.com/airplanedev/lib/pkg/api"
+	"github.com/airplanedev/lib/pkg/deploy/archive"
+	"github.com/pkg/errors"
+)
+
+// filteredArchiver uploads build archives that only contain the files that walkArchive
+// includes, so that .airplaneignore is honored without copying the build root first.
+type filteredArchiver struct {
+	client   api.APIClient
+	uploader archive.Uploader
+}
+
+var _ archive.Archiver = &filteredArchiver{}
+
+// Archive implementation.
+func (a *filteredArchiver) Archive(ctx context.Context, root string) (string, int, error) {
+	f, err := os.CreateTemp("", "airplane-archive-*.tar.gz")
+	if err != nil {
+		return "", 0, errors.Wrap(err, "creating archive")
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	gw := gzip.NewWriter(f)
+	if err := writeArchive(gw, root); err != nil {
+		return "", 0, err
+	}
+	if err := gw.Close(); err != nil {
+		return "", 0, errors.Wrapf(err, "archiving %s", root)
+	}
+	sizeBytes, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return "", 0, errors.Wrap(err, "getting archive size")
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", 0, errors.Wrap(err, "reading archive")
+	}
+
+	upload, err := a.client.CreateBuildUpload(ctx, libapi.CreateBuildUploadRequest{
+		SizeBytes: int(sizeBytes),
+	})
+	if err != nil {
+		return "", 0, errors.Wrap(err, "creating upload")
+	}
+	if err := a.uploader.Upload(ctx, upload.WriteOnlyURL, f); err != nil {
+		return "", 0, errors.Wrap(err, "uploading archive")
+	}
+	return upload.Upload.ID, int(sizeBytes), nil
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/airplanedev/lib/pkg/build"
 	"github.com/airplanedev/lib/pkg/deploy/discover"
 	"github.com/airplanedev/lib/pkg/deploy/taskdir/definitions"
+	"github.com/dustin/go-humanize"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -29,13 +30,43 @@ type config struct {
 	paths        []string
 	local        bool
 	changedFiles utils.NewlineFileValue
+	// since is a git ref. If set, only tasks and views with files that changed since the ref
+	// are deployed.
+	since string
 
 	upgradeInterpolation bool
+	// buildConcurrency is the maximum number of images that are built at once with local.
+	buildConcurrency int
 
 	assumeYes bool
 	assumeNo  bool
 
-	envSlug string
+	// plan prints what would be deployed instead of deploying.
+	plan bool
+	// inspectArchive lists the contents of build archives instead of deploying.
+	inspectArchive bool
+	// maxArchiveSize is the size, e.g. "100MB", that build archives may not exceed. It is
+	// parsed into maxArchiveBytes.
+	maxArchiveSize  string
+	maxArchiveBytes int64
+	// reports are the reports to write, e.g. `junit=report.xml` or `github`. They are parsed
+	// into junitReportPath and githubReport.
+	reports         []string
+	junitReportPath string
+	githubReport    bool
+	// allowSecrets deploys even if the secret scanner finds possible secrets in build roots.
+	allowSecrets bool
+	// noArchiveCache uploads every build root, even if it was uploaded recently.
+	noArchiveCache bool
+	// noWait prints the ID of the deployment instead of waiting for it to finish.
+	noWait bool
+
+	// envSlug is the environment to deploy to. If several environments are given, it is the
+	// first of envSlugs.
+	envSlug  string
+	envSlugs []string
+	// stopOnFailure skips the remaining environments once a deploy to one of envSlugs fails.
+	stopOnFailure bool
 }
 
 func New(c *cli.Config) *cobra.Command {
@@ -55,6 +86,13 @@ func New(c *cli.Config) *cobra.Command {
 			airplane tasks deploy --local ./my_task.task.yml
 			airplane tasks deploy my-directory
 			airplane tasks deploy ./my_task1.task.yml ./my_task2.task.json my-directory
+			airplane tasks deploy --plan -o json my-directory
+			airplane tasks deploy --since origin/main my-directory
+			airplane tasks deploy --no-wait my-directory
+			airplane tasks deploy --inspect-archive my-directory
+			airplane tasks deploy --report junit=deploy.xml --report github my-directory
+			airplane tasks deploy --env staging,prod --stop-on-failure my-directory
+			airplane tasks deploy promote --from staging --to prod
 		`),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) > 0 {
@@ -71,8 +109,17 @@ func New(c *cli.Config) *cobra.Command {
 	}
 
 	cmd.Flags().BoolVarP(&cfg.local, "local", "L", false, "use a local Docker daemon (instead of an Airplane-hosted builder)")
+	cmd.Flags().IntVar(&cfg.buildConcurrency, "build-concurrency", 4, "The maximum number of images to build at once with --local.")
 	cmd.Flags().BoolVar(&cfg.upgradeInterpolation, "jst", false, "Upgrade interpolation to JST")
-	cmd.Flags().Var(&cfg.changedFiles, "changed-files", "A file with a list of file paths that were changed, one path per line. Only tasks with changed files will be deployed")
+	cmd.Flags().Var(&cfg.changedFiles, "changed-files", "A file with a list of file paths that were changed, one path per line. Only tasks and views with changed files will be deployed")
+	cmd.Flags().StringVar(&cfg.since, "since", "", "A git ref, e.g. origin/main. Only tasks and views with files that changed since HEAD diverged from the ref will be deployed")
+	cmd.Flags().BoolVar(&cfg.plan, "plan", false, "Print a plan of what would be deployed without deploying anything. Exits with code 2 if anything would change.")
+	cmd.Flags().BoolVar(&cfg.inspectArchive, "inspect-archive", false, "List the files in each build archive and their sizes without deploying anything.")
+	cmd.Flags().StringVar(&cfg.maxArchiveSize, "max-archive-size", "", "Fail the deploy if a build archive is larger than this size, e.g. 100MB.")
+	cmd.Flags().StringArrayVar(&cfg.reports, "report", nil, "Write a report of the deployment for CI: junit=<path> writes a JUnit XML report, github prints GitHub Actions annotations for failures. Can be repeated.")
+	cmd.Flags().BoolVar(&cfg.allowSecrets, "allow-secrets", false, "Deploy even if possible secrets are found in build archives.")
+	cmd.Flags().BoolVar(&cfg.noArchiveCache, "no-archive-cache", false, "Upload every build archive, even if an identical archive was uploaded recently.")
+	cmd.Flags().BoolVar(&cfg.noWait, "no-wait", false, "Print the ID of the deployment and exit without waiting for it to finish.")
 	cmd.Flags().BoolVarP(&cfg.assumeYes, "yes", "y", false, "True to specify automatic yes to prompts.")
 	cmd.Flags().BoolVarP(&cfg.assumeNo, "no", "n", false, "True to specify automatic no to prompts.")
 
@@ -83,7 +130,10 @@ func New(c *cli.Config) *cobra.Command {
 		logger.Debug("error: %s", err)
 	}
 
-	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment to query. Defaults to your team's default environment.")
+	cmd.Flags().StringSliceVar(&cfg.envSlugs, "env", nil, "The slug of the environment to deploy to. Defaults to your team's default environment. Pass a comma-separated list, e.g. staging,prod, to deploy to several environments in order.")
+	cmd.Flags().BoolVar(&cfg.stopOnFailure, "stop-on-failure", false, "When deploying to several environments, skip the remaining environments once a deploy fails.")
+
+	cmd.AddCommand(newPromoteCmd(c))
 
 	return cmd
 }
@@ -105,9 +155,39 @@ func run(ctx context.Context, cfg config) error {
 		return errors.New("Cannot specify both --yes and --no")
 	}
 
+	if err := parseEnvSlugs(&cfg); err != nil {
+		return err
+	}
+
+	if cfg.buildConcurrency < 1 {
+		return errors.New("--build-concurrency must be at least 1")
+	}
+
+	if err := parseReports(&cfg); err != nil {
+		return err
+	}
+
+	if cfg.maxArchiveSize != "" {
+		n, err := humanize.ParseBytes(cfg.maxArchiveSize)
+		if err != nil {
+			return errors.Wrap(err, "parsing --max-archive-size")
+		}
+		cfg.maxArchiveBytes = int64(n)
+	}
+
 	l := logger.NewStdErrLogger(logger.StdErrLoggerOpts{WithLoader: true})
 	defer l.StopLoader()
 
+	if cfg.since != "" {
+		// Diff the repos of the deployed paths, which need not contain the working directory.
+		files, err := GetChangedFilesSince(cfg.paths, cfg.since)
+		if err != nil {
+			return err
+		}
+		l.Debug("Files changed since %s: %v", cfg.since, files)
+		cfg.changedFiles = append(cfg.changedFiles, files...)
+	}
+
 	createdTasks := map[string]bool{}
 	d := &discover.Discoverer{
 		TaskDiscoverers: []discover.TaskDiscoverer{},
@@ -161,11 +241,16 @@ func run(ctx context.Context, cfg config) error {
 		}
 	}
 
-	return NewDeployer(cfg, l, DeployerOpts{}).Deploy(ctx, taskConfigs, viewConfigs, createdTasks)
+	return NewDeployer(cfg, l, DeployerOpts{}).Deploy(ctx, taskConfigs, viewConfigs, createdTasks, createdViews)
 }
 
 func HandleMissingTask(cfg config, l logger.LoggerWithLoader, createdTasks *map[string]bool) func(ctx context.Context, def definitions.DefinitionInterface) (*libapi.TaskMetadata, error) {
 	return func(ctx context.Context, def definitions.DefinitionInterface) (*libapi.TaskMetadata, error) {
+		if cfg.plan {
+			// Plans never create anything: the task is included without an ID so that the plan
+			// reports it as new.
+			return &libapi.TaskMetadata{Slug: def.GetSlug()}, nil
+		}
 		if utils.CanPrompt() {
 			wasActive := l.StopLoader()
 			question := fmt.Sprintf("A task with slug %s does not exist. Would you like to create one?", def.GetSlug())
@@ -230,6 +315,11 @@ func HandleMissingTask(cfg config, l logger.LoggerWithLoader, createdTasks *map[
 
 func HandleMissingView(cfg config, l logger.LoggerWithLoader, createdViews *map[string]bool) func(ctx context.Context, def definitions.ViewDefinition) (*libapi.View, error) {
 	return func(ctx context.Context, def definitions.ViewDefinition) (*libapi.View, error) {
+		if cfg.plan {
+			// Plans never create anything: the view is included without an ID so that the plan
+			// reports it as new.
+			return &libapi.View{Slug: def.Slug}, nil
+		}
 		if utils.CanPrompt() {
 			wasActive := l.StopLoader()
 			question := fmt.Sprintf("A view with slug %s does not exist. Would you like to create one?", def.Slug)
@@ -313,7 +403,7 @@ func findDefinitionForScript(ctx context.Context, cfg config, l logger.LoggerWit
 				defer l.StartLoader()
 			}
 
-			question := fmt.Sprintf("A definition file for task %q exists (%s).\nWould you like to use it?", taskConfig.Def.GetSlug(), relpath(path))
+			question := fmt.Sprintf("A definition file for task %q exists (%s).\nWould you like to use it?", taskConfig.Def.GetSlug(), RelPath(path))
 			ok, err := utils.ConfirmWithAssumptions(question, cfg.assumeYes, cfg.assumeNo)
 			if err != nil {
 				return nil, err
@@ -22,11 +22,9 @@ import (
 	"github.com/airplanedev/lib/pkg/deploy/taskdir/definitions"
 	"github.com/dustin/go-humanize"
 	"github.com/go-git/go-git/v5"
-	"github.com/hexops/gotextdiff"
-	"github.com/hexops/gotextdiff/myers"
-	"github.com/hexops/gotextdiff/span"
 	"github.com/pkg/errors"
 	"golang.org/x/sync/errgroup"
+	"gopkg.in/yaml.v3"
 )
 
 type deployer struct {
@@ -35,6 +33,10 @@ type deployer struct {
 	logger       logger.LoggerWithLoader
 	archiver     archive.Archiver
 	repoGetter   GitRepoGetter
+	// archiveCache is nil if uploads should not be cached.
+	archiveCache *archiveCache
+	// report is nil if no reports were requested.
+	report *deployReport
 }
 
 type DeployerOpts struct {
@@ -51,9 +53,21 @@ func NewDeployer(cfg config, l logger.LoggerWithLoader, opts DeployerOpts) *depl
 	if opts.BuildCreator != nil {
 		bc = opts.BuildCreator
 	}
-	a := archive.NewAPIArchiver(l, cfg.client, &archive.HttpUploader{})
+	var a archive.Archiver = &filteredArchiver{
+		client:   cfg.client,
+		uploader: &archive.HttpUploader{},
+	}
+	var ac *archiveCache
 	if opts.Archiver != nil {
 		a = opts.Archiver
+	} else if !cfg.noArchiveCache {
+		// Only uploads made by the filtered archiver can be reused, and only by the team that made
+		// them. If the team is unknown, uploads are not cached.
+		if namespace := archiveCacheNamespace(cfg.root); namespace != "" {
+			ac = newArchiveCache(l, conf.DefaultArchiveCachePath(), namespace)
+		} else {
+			l.Debug("Not caching archives: unable to determine team")
+		}
 	}
 	var rg GitRepoGetter
 	rg = &FileGitRepoGetter{}
@@ -66,18 +80,23 @@ func NewDeployer(cfg config, l logger.LoggerWithLoader, opts DeployerOpts) *depl
 		logger:       l,
 		archiver:     a,
 		repoGetter:   rg,
+		archiveCache: ac,
 	}
 }
 
 // Deploy deploys all configs.
-func (d *deployer) Deploy(ctx context.Context, taskConfigs []discover.TaskConfig, viewConfigs []discover.ViewConfig, createdTasks map[string]bool) error {
+func (d *deployer) Deploy(ctx context.Context, taskConfigs []discover.TaskConfig, viewConfigs []discover.ViewConfig, createdTasks, createdViews map[string]bool) error {
 	var err error
-	if len(d.cfg.changedFiles) > 0 {
+	// With --since, an empty list of changed files means that nothing changed.
+	if len(d.cfg.changedFiles) > 0 || d.cfg.since != "" {
 		taskConfigs, err = d.filterTaskConfigsByChangedFiles(ctx, taskConfigs)
 		if err != nil {
 			return err
 		}
-		// TODO implement for views
+		viewConfigs, err = d.filterViewConfigsByChangedFiles(ctx, viewConfigs)
+		if err != nil {
+			return err
+		}
 	}
 
 	if len(taskConfigs) == 0 && len(viewConfigs) == 0 {
@@ -85,21 +104,101 @@ func (d *deployer) Deploy(ctx context.Context, taskConfigs []discover.TaskConfig
 		return nil
 	}
 
-	if err := d.printPreDeploySummary(ctx, taskConfigs, viewConfigs, createdTasks); err != nil {
+	if d.cfg.plan {
+		return d.printPlan(ctx, taskConfigs, viewConfigs, createdTasks, createdViews)
+	}
+
+	if d.cfg.inspectArchive {
+		return d.inspectArchives(taskConfigs, viewConfigs)
+	}
+
+	// The report is created before anything is built, so that it covers failures that happen
+	// before a deployment is created too.
+	if d.cfg.junitReportPath != "" || d.cfg.githubReport {
+		d.report = newDeployReport()
+		for _, tc := range taskConfigs {
+			d.report.add("tasks", tc.Def.GetSlug(), tc.Def.GetDefnFilePath())
+		}
+		for _, vc := range viewConfigs {
+			d.report.add("views", vc.Def.Slug, "")
+		}
+	}
+	err = d.deploy(ctx, taskConfigs, viewConfigs, createdTasks, createdViews)
+	if d.report != nil {
+		d.report.abort(err)
+		if rerr := d.writeReports(); rerr != nil {
+			if err != nil {
+				d.logger.Warning("Failed to write reports: %v", rerr)
+				return err
+			}
+			return rerr
+		}
+	}
+	return err
+}
+
+// deploy checks, builds and deploys configs.
+func (d *deployer) deploy(ctx context.Context, taskConfigs []discover.TaskConfig, viewConfigs []discover.ViewConfig, createdTasks, createdViews map[string]bool) error {
+	roots, _, err := getBuildRoots(taskConfigs, viewConfigs)
+	if err != nil {
+		return err
+	}
+	if err := d.checkSecrets(roots); err != nil {
+		return err
+	}
+	if err := d.checkPolicies(taskConfigs); err != nil {
+		return err
+	}
+
+	if len(d.envSlugs()) > 1 {
+		return d.deployToEnvs(ctx, taskConfigs, viewConfigs, createdTasks, createdViews)
+	}
+
+	if err := d.printPreDeploySummary(ctx, taskConfigs, viewConfigs, createdTasks, createdViews); err != nil {
 		if err == skippedDeployErr {
 			return nil
 		}
 		return err
 	}
 
-	var uploadIDs map[string]string
-	if !d.cfg.local {
-		uploadIDs, err = d.tarAndUploadBatch(ctx, taskConfigs, viewConfigs)
-		if err != nil {
+	if err := d.checkConfigVars(ctx, taskConfigs); err != nil {
+		return err
+	}
+	uploadIDs, images, err := d.build(ctx, taskConfigs, viewConfigs)
+	if err != nil {
+		return err
+	}
+	_, err = d.createDeployment(ctx, taskConfigs, viewConfigs, uploadIDs, images)
+	return err
+}
+
+// build uploads the build roots of the configs that need building or, with --local, builds their
+// images. It returns upload IDs keyed by task or view ID and images keyed by task slug.
+func (d *deployer) build(ctx context.Context, taskConfigs []discover.TaskConfig, viewConfigs []discover.ViewConfig) (uploadIDs, images map[string]string, err error) {
+	if d.cfg.local {
+		images, err = d.buildLocalImages(ctx, taskConfigs)
+		return nil, images, err
+	}
+	uploadIDs, err = d.tarAndUploadBatch(ctx, taskConfigs, viewConfigs)
+	return uploadIDs, nil, err
+}
+
+// checkConfigVars checks that the configs that tasks read exist in the deployer's environment and
+// asks to create any that are missing.
+func (d *deployer) checkConfigVars(ctx context.Context, taskConfigs []discover.TaskConfig) error {
+	for _, tc := range taskConfigs {
+		if err := ensureConfigVarsExist(ctx, d.cfg.client, d.logger, tc.Def, d.cfg.envSlug); err != nil {
 			return err
 		}
 	}
+	return nil
+}
 
+// createDeployment deploys configs that have been built to the deployer's environment and waits
+// for the deployment to finish, unless --no-wait is set. It returns the ID of the deployment, or
+// an empty string if no deployment was created.
+func (d *deployer) createDeployment(ctx context.Context, taskConfigs []discover.TaskConfig, viewConfigs []discover.ViewConfig, uploadIDs, images map[string]string) (string, error) {
+	var err error
 	var tasksToDeploy []api.DeployTask
 	gitRoots := make(map[string]bool)
 	var repo *git.Repository
@@ -110,7 +209,10 @@ func (d *deployer) Deploy(ctx context.Context, taskConfigs []discover.TaskConfig
 		}
 		taskToDeploy, err := d.getDeployTask(ctx, tc, uploadIDs[tc.TaskID], repo)
 		if err != nil {
-			return err
+			return "", err
+		}
+		if image, ok := images[tc.Def.GetSlug()]; ok {
+			taskToDeploy.UpdateTaskRequest.Image = &image
 		}
 		tasksToDeploy = append(tasksToDeploy, taskToDeploy)
 
@@ -141,7 +243,7 @@ func (d *deployer) Deploy(ctx context.Context, taskConfigs []discover.TaskConfig
 
 		relEntrypoint, err := filepath.Rel(vc.Root, vc.Def.Entrypoint)
 		if err != nil {
-			return errors.Wrap(err, "relativizing entrypoint")
+			return "", errors.Wrap(err, "relativizing entrypoint")
 		}
 
 		// We set a default API host here so that the unit test doesn't crash, but
@@ -211,25 +313,22 @@ func (d *deployer) Deploy(ctx context.Context, taskConfigs []discover.TaskConfig
 		EnvSlug:     d.cfg.envSlug,
 	})
 	if err != nil {
-		return err
+		return "", err
+	}
+
+	if d.report != nil {
+		d.report.deploymentID = resp.Deployment.ID
 	}
 
 	d.deployLog(ctx, api.LogLevelInfo, deployLogReq{msg: logger.Gray("Creating deployment...")})
 	d.logger.Log(logger.Purple(fmt.Sprintf("\nView deployment: %s\n", d.cfg.client.DeploymentURL(resp.Deployment.ID, d.cfg.envSlug))))
 
-	err = d.waitForDeploy(ctx, d.cfg.client, resp.Deployment.ID)
-	if errors.Is(err, context.Canceled) {
-		// Since `ctx` is cancelled, use a fresh context to cancel the deployment.
-		//nolint: contextcheck
-		cerr := d.cfg.client.CancelDeployment(context.Background(), api.CancelDeploymentRequest{ID: resp.Deployment.ID})
-		if cerr != nil {
-			d.logger.Warning("Failed to cancel deployment: %v", cerr)
-		} else {
-			d.logger.Log("Cancelled deployment")
-		}
+	if d.cfg.noWait {
+		fmt.Println(resp.Deployment.ID)
+		return resp.Deployment.ID, nil
 	}
 
-	return err
+	return resp.Deployment.ID, d.waitForDeploy(ctx, d.cfg.client, resp.Deployment.ID)
 }
 
 func (d *deployer) getDeployTask(ctx context.Context, tc discover.TaskConfig, uploadID string, repo *git.Repository) (taskToDeploy api.DeployTask, rErr error) {
@@ -311,17 +410,11 @@ More information: https://apn.sh/jst-upgrade`)
 		}
 	}
 
-	err = ensureConfigVarsExist(ctx, client, d.logger, tc.Def, d.cfg.envSlug)
-	if err != nil {
-		return api.DeployTask{}, err
-	}
-
 	env, err := tc.Def.GetEnv()
 	if err != nil {
 		return api.DeployTask{}, err
 	}
 
-	var image *string
 	if ok, err := libBuild.NeedsBuilding(kind); err != nil {
 		return api.DeployTask{}, err
 	} else if ok {
@@ -331,30 +424,12 @@ More information: https://apn.sh/jst-upgrade`)
 		if ep, ok := buildConfig["entrypoint"].(string); ok {
 			buildConfig["entrypoint"] = filepath.ToSlash(ep)
 		}
-
-		if d.cfg.local {
-			resp, err := d.buildCreator.CreateBuild(ctx, build.Request{
-				Client:  client,
-				TaskID:  tc.TaskID,
-				Root:    tc.TaskRoot,
-				Def:     tc.Def,
-				TaskEnv: env,
-				Shim:    true,
-			})
-			if err != nil {
-				return api.DeployTask{}, err
-			}
-			image = &resp.ImageURL
-		}
 	}
 
 	utr, err := tc.Def.GetUpdateTaskRequest(ctx, d.cfg.client)
 	if err != nil {
 		return api.DeployTask{}, err
 	}
-	if image != nil {
-		utr.Image = image
-	}
 	utr.InterpolationMode = &interpolationMode
 	utr.EnvSlug = d.cfg.envSlug
 
@@ -424,6 +499,18 @@ func (d *deployer) tarAndUploadBatch(ctx context.Context, taskConfigs []discover
 	}
 
 	groupErr := g.Wait()
+	if groupErr == nil && d.archiveCache != nil {
+		if d.archiveCache.skipped > 0 {
+			d.deployLog(ctx, api.LogLevelInfo, deployLogReq{msg: logger.Gray("Skipped %d unchanged build %s, saving %s of uploads.",
+				d.archiveCache.skipped,
+				pluralize(d.archiveCache.skipped, "archive", "archives"),
+				humanize.Bytes(uint64(d.archiveCache.savedBytes)),
+			)})
+		}
+		if err := d.archiveCache.save(); err != nil {
+			d.logger.Debug("Failed to save archive cache: %v", err)
+		}
+	}
 	return uploadIDs, groupErr
 }
 
@@ -432,12 +519,40 @@ func (d *deployer) tarAndUpload(ctx context.Context, slug, root string) (string,
 		return "", err
 	}
 
+	if d.cfg.maxArchiveBytes > 0 {
+		files, err := listArchiveFiles(root)
+		if err != nil {
+			return "", err
+		}
+		if err := checkArchiveSize(root, files, d.cfg.maxArchiveBytes); err != nil {
+			return "", err
+		}
+	}
+
+	var hash string
+	if d.archiveCache != nil {
+		var err error
+		if hash, err = d.archiveCache.hash(root); err != nil {
+			d.logger.Debug("Failed to hash %s: %v", root, err)
+		} else if u, ok := d.archiveCache.get(hash); ok {
+			d.deployLog(ctx, api.LogLevelInfo, deployLogReq{slug, logger.Gray("Skipped uploading unchanged %s.", root)})
+			return u.UploadID, nil
+		}
+	}
+
 	d.deployLog(ctx, api.LogLevelInfo, deployLogReq{slug, logger.Gray("Packaging and uploading %s...", root)})
 
 	uploadID, sizeBytes, err := d.archiver.Archive(ctx, root)
 	if err != nil {
 		return "", err
 	}
+	if hash != "" {
+		d.archiveCache.put(hash, conf.CachedUpload{
+			UploadID:  uploadID,
+			SizeBytes: sizeBytes,
+			CreatedAt: time.Now(),
+		})
+	}
 	if sizeBytes > 0 {
 		d.deployLog(ctx, api.LogLevelInfo, deployLogReq{slug, logger.Gray("Uploaded %s build archive.",
 			humanize.Bytes(uint64(sizeBytes)),
@@ -451,7 +566,7 @@ func (d *deployer) filterTaskConfigsByChangedFiles(ctx context.Context, taskConf
 	var filteredTaskConfigs []discover.TaskConfig
 	for _, tc := range taskConfigs {
 		if tc.TaskRoot != "" {
-			contains, err := containsFile(tc.TaskRoot, d.cfg.changedFiles)
+			contains, err := rootHasChanges(tc.TaskRoot, d.cfg.changedFiles)
 			if err != nil {
 				return nil, err
 			}
@@ -480,9 +595,41 @@ func (d *deployer) filterTaskConfigsByChangedFiles(ctx context.Context, taskConf
 	return filteredTaskConfigs, nil
 }
 
+// filterViewConfigsByChangedFiles filters out any views that don't have changed files.
+func (d *deployer) filterViewConfigsByChangedFiles(ctx context.Context, viewConfigs []discover.ViewConfig) ([]discover.ViewConfig, error) {
+	var filteredViewConfigs []discover.ViewConfig
+	for _, vc := range viewConfigs {
+		// View definition files live in the view's root directory.
+		contains, err := rootHasChanges(vc.Root, d.cfg.changedFiles)
+		if err != nil {
+			return nil, err
+		}
+		if contains {
+			filteredViewConfigs = append(filteredViewConfigs, vc)
+		}
+	}
+	if len(viewConfigs) != len(filteredViewConfigs) {
+		d.logger.Log("Changed files specified. Filtered %d view(s) to %d affected view(s)", len(viewConfigs), len(filteredViewConfigs))
+	}
+	return filteredViewConfigs, nil
+}
+
 var skippedDeployErr = errors.New("Skipped deploy")
 
-func (d *deployer) printPreDeploySummary(ctx context.Context, taskConfigs []discover.TaskConfig, viewConfigs []discover.ViewConfig, createdTasks map[string]bool) error {
+func (d *deployer) printPreDeploySummary(ctx context.Context, taskConfigs []discover.TaskConfig, viewConfigs []discover.ViewConfig, createdTasks, createdViews map[string]bool) error {
+	hasDiff, err := d.printDeploySummary(ctx, taskConfigs, viewConfigs, createdTasks, createdViews)
+	if err != nil {
+		return err
+	}
+	if hasDiff {
+		return d.confirmDeployment(ctx)
+	}
+	return nil
+}
+
+// printDeploySummary prints the configs that are about to be deployed to the deployer's
+// environment and the changes to their definitions. It returns true if any definition changed.
+func (d *deployer) printDeploySummary(ctx context.Context, taskConfigs []discover.TaskConfig, viewConfigs []discover.ViewConfig, createdTasks, createdViews map[string]bool) (bool, error) {
 	noun := "task"
 	if len(taskConfigs) > 1 {
 		noun = fmt.Sprintf("%ss", noun)
@@ -503,14 +650,14 @@ func (d *deployer) printPreDeploySummary(ctx context.Context, taskConfigs []disc
 		if _, err := tc.Def.Entrypoint(); err == definitions.ErrNoEntrypoint {
 			// nothing
 		} else if err != nil {
-			return err
+			return false, err
 		} else {
-			d.logger.Log("Root directory: %s", relpath(tc.TaskRoot))
+			d.logger.Log("Root directory: %s", RelPath(tc.TaskRoot))
 		}
 
 		// Log definition file if this came from a definition file.
 		if tc.Source == discover.ConfigSourceDefn {
-			defPath := relpath(tc.Def.GetDefnFilePath())
+			defPath := RelPath(tc.Def.GetDefnFilePath())
 			d.logger.Log("Definition file: %s", defPath)
 		}
 
@@ -520,7 +667,7 @@ func (d *deployer) printPreDeploySummary(ctx context.Context, taskConfigs []disc
 		if tc.Source == discover.ConfigSourceDefn {
 			difflines, err := d.getDefinitionDiff(ctx, tc, createdTasks[tc.TaskID])
 			if err != nil {
-				return err
+				return false, err
 			}
 
 			if len(difflines) == 1 {
@@ -545,18 +692,27 @@ func (d *deployer) printPreDeploySummary(ctx context.Context, taskConfigs []disc
 	if len(viewConfigs) > 0 {
 		d.logger.Log("Deploying %v %v:\n", len(viewConfigs), noun)
 	}
-	// TODO diff view configs and update hasDiff if any views have changed.
 	for _, vc := range viewConfigs {
 		d.logger.Log(logger.Bold(vc.Def.Slug))
-		d.logger.Log("Root directory: %s", relpath(vc.Root))
-		d.logger.Log("")
-	}
+		d.logger.Log("Root directory: %s", RelPath(vc.Root))
 
-	if hasDiff {
-		return d.confirmDeployment(ctx)
+		difflines, err := d.getViewDefinitionDiff(ctx, vc, createdViews[vc.ID])
+		if err != nil {
+			return false, err
+		}
+		if len(difflines) == 1 {
+			d.logger.Log(difflines[0])
+		} else if len(difflines) > 1 {
+			for _, line := range difflines {
+				d.logger.Log("  %s", line)
+			}
+			hasDiff = true
+		}
+
+		d.logger.Log("")
 	}
 
-	return nil
+	return hasDiff, nil
 }
 
 func (d *deployer) getDefinitionDiff(ctx context.Context, taskConfig discover.TaskConfig, isNew bool) ([]string, error) {
@@ -564,29 +720,18 @@ func (d *deployer) getDefinitionDiff(ctx context.Context, taskConfig discover.Ta
 		return []string{"(new task)"}, nil
 	}
 
-	task, err := d.cfg.client.GetTask(ctx, libapi.GetTaskRequest{
-		Slug:    taskConfig.Def.GetSlug(),
-		EnvSlug: d.cfg.envSlug,
-	})
+	oldYAML, err := d.getCurrentDefinition(ctx, taskConfig.Def.GetSlug())
 	if err != nil {
-		if _, ok := err.(*libapi.TaskMissingError); ok {
-			// The task is being promoted into a new environment, proceed as normal.
-			return []string{"(task created in new environment)"}, nil
-		}
 		return nil, err
 	}
+	if oldYAML == nil {
+		// The task is being promoted into a new environment, proceed as normal.
+		return []string{"(task created in new environment)"}, nil
+	}
 
-	defPath := relpath(taskConfig.Def.GetDefnFilePath())
+	defPath := RelPath(taskConfig.Def.GetDefnFilePath())
 	defPath = strings.TrimPrefix(defPath, "./")
 
-	oldDef, err := definitions.NewDefinitionFromTask_0_3(ctx, d.cfg.client, task)
-	if err != nil {
-		return nil, err
-	}
-	oldYAML, err := oldDef.Marshal(definitions.DefFormatYAML)
-	if err != nil {
-		return nil, errors.Wrap(err, "Error marshalling current task definition")
-	}
 	oldYAMLStr := string(oldYAML)
 	oldLabel := fmt.Sprintf("a/%s", defPath)
 
@@ -597,27 +742,107 @@ func (d *deployer) getDefinitionDiff(ctx context.Context, taskConfig discover.Ta
 	newYAMLStr := string(newYAML)
 	newLabel := fmt.Sprintf("b/%s", defPath)
 
-	edits := myers.ComputeEdits(span.URI(oldLabel), oldYAMLStr, newYAMLStr)
-	edits = gotextdiff.LineEdits(oldYAMLStr, edits)
-	diff := fmt.Sprint(gotextdiff.ToUnified(oldLabel, newLabel, oldYAMLStr, edits))
-	if diff == "" {
+	difflines := utils.UnifiedDiff(oldLabel, newLabel, oldYAMLStr, newYAMLStr)
+	if difflines == nil {
 		return []string{"(no changes to task definition)"}, nil
 	}
+	return difflines, nil
+}
 
-	// Log deletes in red & additions in green.
-	difflines := strings.Split(diff, "\n")
-	pretty := make([]string, len(difflines))
-	for i, line := range difflines {
-		if strings.HasPrefix(line, "-") {
-			pretty[i] = logger.Red("%s", line)
-		} else if strings.HasPrefix(line, "+") {
-			pretty[i] = logger.Green("%s", line)
-		} else {
-			pretty[i] = line
+func (d *deployer) getViewDefinitionDiff(ctx context.Context, viewConfig discover.ViewConfig, isNew bool) ([]string, error) {
+	if isNew {
+		return []string{"(new view)"}, nil
+	}
+
+	oldYAML, err := d.getCurrentViewDefinition(ctx, viewConfig.Def.Slug)
+	if err != nil {
+		return nil, err
+	}
+	if oldYAML == nil {
+		// The view is being promoted into a new environment, proceed as normal.
+		return []string{"(view created in new environment)"}, nil
+	}
+	newYAML, err := marshalViewDefinition(viewConfig.Def)
+	if err != nil {
+		return nil, err
+	}
+
+	label := viewConfig.Def.Slug
+	difflines := utils.UnifiedDiff("a/"+label, "b/"+label, string(oldYAML), string(newYAML))
+	if difflines == nil {
+		return []string{"(no changes to view definition)"}, nil
+	}
+	return difflines, nil
+}
+
+// viewDefinitionFields are the fields of a view definition that are stored on the view.
+type viewDefinitionFields struct {
+	Slug        string         `yaml:"slug"`
+	Name        string         `yaml:"name"`
+	Description string         `yaml:"description,omitempty"`
+	EnvVars     libapi.TaskEnv `yaml:"envVars,omitempty"`
+}
+
+func marshalViewDefinition(def definitions.ViewDefinition) ([]byte, error) {
+	buf, err := yaml.Marshal(viewDefinitionFields{
+		Slug:        def.Slug,
+		Name:        def.Name,
+		Description: def.Description,
+		EnvVars:     def.EnvVars,
+	})
+	return buf, errors.Wrap(err, "Error marshalling view definition")
+}
+
+// getCurrentViewDefinition returns the YAML definition of the view as it is currently deployed,
+// or nil if the view does not exist.
+func (d *deployer) getCurrentViewDefinition(ctx context.Context, slug string) ([]byte, error) {
+	view, err := d.cfg.client.GetView(ctx, libapi.GetViewRequest{Slug: slug})
+	if err != nil {
+		if _, ok := err.(*libapi.ViewMissingError); ok {
+			return nil, nil
 		}
+		return nil, err
 	}
+	buf, err := yaml.Marshal(viewDefinitionFields{
+		Slug:        view.Slug,
+		Name:        view.Name,
+		Description: view.Description,
+		EnvVars:     view.EnvVars,
+	})
+	return buf, errors.Wrap(err, "Error marshalling current view definition")
+}
 
-	return pretty, nil
+// getCurrentDefinition returns the YAML definition of the task as it is currently deployed, or
+// nil if the task does not exist in the environment.
+func (d *deployer) getCurrentDefinition(ctx context.Context, slug string) ([]byte, error) {
+	return getDeployedDefinition(ctx, d.cfg.client, d.cfg.envSlug, slug)
+}
+
+// getDeployedDefinition returns the YAML definition of the task that is deployed to envSlug, or
+// nil if the task does not exist in the environment.
+func getDeployedDefinition(ctx context.Context, client api.APIClient, envSlug, slug string) ([]byte, error) {
+	task, err := client.GetTask(ctx, libapi.GetTaskRequest{
+		Slug:    slug,
+		EnvSlug: envSlug,
+	})
+	if err != nil {
+		if _, ok := err.(*libapi.TaskMissingError); ok {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return marshalTaskDefinition(ctx, client, task)
+}
+
+// marshalTaskDefinition returns the YAML definition of a deployed task.
+func marshalTaskDefinition(ctx context.Context, client api.APIClient, task libapi.Task) ([]byte, error) {
+	def, err := definitions.NewDefinitionFromTask_0_3(ctx, client, task)
+	if err != nil {
+		return nil, err
+	}
+	buf, err := def.Marshal(definitions.DefFormatYAML)
+	return buf, errors.Wrap(err, "Error marshalling current task definition")
 }
 
 func (d *deployer) confirmDeployment(ctx context.Context) error {
@@ -641,10 +866,35 @@ func (d *deployer) confirmDeployment(ctx context.Context) error {
 	return nil
 }
 
-func (d *deployer) waitForDeploy(ctx context.Context, client api.APIClient, deploymentID string) error {
+// WaitForDeployment streams the logs of a deployment that was not created by a deployer, e.g. a
+// rollback, until it finishes.
+func WaitForDeployment(ctx context.Context, client api.APIClient, l logger.LoggerWithLoader, deploymentID string) error {
+	d := &deployer{logger: l}
+	return d.waitForDeploy(ctx, client, deploymentID)
+}
+
+// waitForDeploy streams the logs of a deployment until it finishes. If ctx is cancelled, e.g.
+// because the user interrupted the CLI, the deployment is cancelled.
+func (d *deployer) waitForDeploy(ctx context.Context, client api.APIClient, deploymentID string) (rErr error) {
 	d.deployLog(ctx, api.LogLevelInfo, deployLogReq{msg: logger.Gray("Waiting for deployer...")})
 
+	defer func() {
+		if ctx.Err() == nil {
+			return
+		}
+		// Since `ctx` is cancelled, use a fresh context to cancel the deployment.
+		//nolint: contextcheck
+		if err := client.CancelDeployment(context.Background(), api.CancelDeploymentRequest{ID: deploymentID}); err != nil {
+			d.logger.Warning("Failed to cancel deployment: %v", err)
+		} else {
+			d.logger.Log("Cancelled deployment")
+		}
+		// Requests that were in flight when ctx was cancelled fail with other errors.
+		rErr = ctx.Err()
+	}()
+
 	t := time.NewTicker(time.Second)
+	defer t.Stop()
 
 	var prevToken string
 	for {
@@ -663,6 +913,9 @@ func (d *deployer) waitForDeploy(ctx context.Context, client api.APIClient, depl
 
 			api.SortLogs(r.Logs)
 			for _, l := range r.Logs {
+				if d.report != nil {
+					d.report.addLog(l)
+				}
 				text := l.Text
 				if strings.HasPrefix(l.Text, "[builder] ") {
 					text = logger.Gray(strings.TrimPrefix(text, "[builder] "))
@@ -675,6 +928,9 @@ func (d *deployer) waitForDeploy(ctx context.Context, client api.APIClient, depl
 			if err != nil {
 				return errors.Wrap(err, "getting deployment")
 			}
+			if d.report != nil && deployment.Done() {
+				d.report.finish(deployment)
+			}
 
 			switch {
 			case deployment.FailedAt != nil:
@@ -728,6 +984,15 @@ func (d *deployer) confirmBuildRoot(root string) error {
 	return nil
 }
 
+// rootHasChanges returns true if the build of the root directory is affected by one of the
+// files: either the file is in the root, or it is a build file in one of its parents.
+func rootHasChanges(root string, filePaths []string) (bool, error) {
+	if contains, err := containsFile(root, filePaths); err != nil || contains {
+		return contains, err
+	}
+	return containsParentBuildFile(root, filePaths)
+}
+
 // containsFile returns true if the directory contains at least one of the files.
 func containsFile(dir string, filePaths []string) (bool, error) {
 	absDir, err := filepath.Abs(dir)
@@ -739,14 +1004,49 @@ func containsFile(dir string, filePaths []string) (bool, error) {
 		if err != nil {
 			return false, errors.Wrapf(err, "calculating absolute path of file %s", cf)
 		}
-		changedFileDir := filepath.Dir(absCF)
-		if strings.HasPrefix(changedFileDir, absDir) {
+		if isWithinDir(filepath.Dir(absCF), absDir) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// buildFiles are files that affect the builds of every task and view below them, e.g. the
+// package.json and lockfile at the root of a monorepo.
+var buildFiles = map[string]bool{
+	"package.json":      true,
+	"package-lock.json": true,
+	"yarn.lock":         true,
+	"pnpm-lock.yaml":    true,
+}
+
+// containsParentBuildFile returns true if one of the files is a build file in a parent of the
+// directory.
+func containsParentBuildFile(dir string, filePaths []string) (bool, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return false, errors.Wrapf(err, "calculating absolute path of directory %s", dir)
+	}
+	for _, cf := range filePaths {
+		if !buildFiles[filepath.Base(cf)] {
+			continue
+		}
+		absCF, err := filepath.Abs(cf)
+		if err != nil {
+			return false, errors.Wrapf(err, "calculating absolute path of file %s", cf)
+		}
+		if isWithinDir(absDir, filepath.Dir(absCF)) {
 			return true, nil
 		}
 	}
 	return false, nil
 }
 
+// isWithinDir returns true if path is dir or is inside of dir.
+func isWithinDir(path, dir string) bool {
+	return path == dir || strings.HasPrefix(path, strings.TrimSuffix(dir, string(filepath.Separator))+string(filepath.Separator))
+}
+
 // equalsFile returns true if the target file is equal to one of the files.
 func equalsFile(target string, files []string) (bool, error) {
 	absTarget, err := filepath.Abs(target)
@@ -765,8 +1065,9 @@ func equalsFile(target string, files []string) (bool, error) {
 	return false, nil
 }
 
-// Relpath returns the relative using root and the cwd.
-func relpath(root string) string {
+// RelPath returns root relative to the working directory, prefixed with ./ so that it reads as
+// a path.
+func RelPath(root string) string {
 	if path, err := os.Getwd(); err == nil {
 		if rp, err := filepath.Rel(path, root); err == nil {
 			if len(rp) == 0 || rp == "." {
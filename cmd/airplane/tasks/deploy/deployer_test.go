@@ -10,6 +10,7 @@ import (
 	"github.com/airplanedev/cli/pkg/api"
 	"github.com/airplanedev/cli/pkg/build"
 	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/airplanedev/cli/pkg/utils"
 	"github.com/airplanedev/cli/pkg/utils/pointers"
 	libapi "github.com/airplanedev/lib/pkg/api"
 	libBuild "github.com/airplanedev/lib/pkg/build"
@@ -19,7 +20,9 @@ import (
 	"github.com/go-git/go-billy/v5/memfs"
 	fixtures "github.com/go-git/go-git-fixtures/v4"
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/cache"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/storage/filesystem"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
@@ -48,6 +51,7 @@ func TestDeploy(t *testing.T) {
 		envVars               map[string]string
 		local                 bool
 		envSlug               string
+		envSlugs              []string
 		gitRepo               *git.Repository
 		getDeploymentResponse *api.Deployment
 		expectedError         error
@@ -315,6 +319,95 @@ func TestDeploy(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "deploys a task to multiple environments",
+			taskConfigs: []discover.TaskConfig{
+				{
+					TaskID:   "tsk123",
+					TaskRoot: fixturesPath,
+					Def: &definitions.Definition_0_3{
+						Name: "My Task",
+						Slug: "my_task",
+						Node: &definitions.NodeDefinition_0_3{},
+					},
+				},
+			},
+			existingTasks: map[string]libapi.Task{"my_task": {ID: "tsk123", Slug: "my_task", Name: "My Task", InterpolationMode: "jst"}},
+			envSlug:       "staging",
+			envSlugs:      []string{"staging", "prod"},
+			deploys: []api.CreateDeploymentRequest{
+				{
+					Tasks: []api.DeployTask{
+						{
+							TaskID: "tsk123",
+							Kind:   "node",
+							BuildConfig: libBuild.BuildConfig{
+								"entrypoint":  "",
+								"nodeVersion": "",
+								"runtime":     libBuild.TaskRuntimeStandard,
+								"shim":        "true",
+							},
+							UploadID: "uploadID",
+							UpdateTaskRequest: libapi.UpdateTaskRequest{
+								Slug:       "my_task",
+								Name:       "My Task",
+								Parameters: libapi.Parameters{},
+								Resources:  map[string]string{},
+								Configs:    &[]libapi.ConfigAttachment{},
+								Kind:       "node",
+								KindOptions: libBuild.KindOptions{
+									"entrypoint":  "",
+									"nodeVersion": "",
+								},
+								ExecuteRules: libapi.UpdateExecuteRulesRequest{
+									DisallowSelfApprove: pointers.Bool(false),
+									RequireRequests:     pointers.Bool(false),
+								},
+								InterpolationMode: pointers.String("jst"),
+								EnvSlug:           "staging",
+								Timeout:           3600,
+							},
+						},
+					},
+					EnvSlug: "staging",
+				},
+				{
+					Tasks: []api.DeployTask{
+						{
+							TaskID: "tsk123",
+							Kind:   "node",
+							BuildConfig: libBuild.BuildConfig{
+								"entrypoint":  "",
+								"nodeVersion": "",
+								"runtime":     libBuild.TaskRuntimeStandard,
+								"shim":        "true",
+							},
+							UploadID: "uploadID",
+							UpdateTaskRequest: libapi.UpdateTaskRequest{
+								Slug:       "my_task",
+								Name:       "My Task",
+								Parameters: libapi.Parameters{},
+								Resources:  map[string]string{},
+								Configs:    &[]libapi.ConfigAttachment{},
+								Kind:       "node",
+								KindOptions: libBuild.KindOptions{
+									"entrypoint":  "",
+									"nodeVersion": "",
+								},
+								ExecuteRules: libapi.UpdateExecuteRulesRequest{
+									DisallowSelfApprove: pointers.Bool(false),
+									RequireRequests:     pointers.Bool(false),
+								},
+								InterpolationMode: pointers.String("jst"),
+								EnvSlug:           "prod",
+								Timeout:           3600,
+							},
+						},
+					},
+					EnvSlug: "prod",
+				},
+			},
+		},
 		{
 			desc: "deploys a task that doesn't need to be built",
 			taskConfigs: []discover.TaskConfig{
@@ -753,6 +846,7 @@ func TestDeploy(t *testing.T) {
 				client:       client,
 				local:        tC.local,
 				envSlug:      tC.envSlug,
+				envSlugs:     tC.envSlugs,
 			}
 			d := NewDeployer(cfg, &logger.MockLogger{}, DeployerOpts{
 				BuildCreator: &build.MockBuildCreator{},
@@ -763,7 +857,7 @@ func TestDeploy(t *testing.T) {
 				err := tC.taskConfigs[i].Def.SetAbsoluteEntrypoint(absEntrypoint)
 				require.NoError(err)
 			}
-			err := d.Deploy(context.Background(), tC.taskConfigs, tC.viewConfigs, map[string]bool{})
+			err := d.Deploy(context.Background(), tC.taskConfigs, tC.viewConfigs, map[string]bool{}, map[string]bool{})
 			if tC.expectedError != nil {
 				assert.Error(err)
 				return
@@ -781,9 +875,11 @@ func TestParseRemote(t *testing.T) {
 	testCases := []struct {
 		desc      string
 		remote    string
+		vendors   map[string]api.GitVendor
 		ownerName string
 		repoName  string
 		vendor    api.GitVendor
+		wantErr   bool
 	}{
 		{
 			desc:      "git http",
@@ -806,6 +902,102 @@ func TestParseRemote(t *testing.T) {
 			repoName:  "airport",
 			vendor:    api.GitVendorGitHub,
 		},
+		{
+			desc:      "gitlab http",
+			remote:    "https://gitlab.com/airplanedev/airport.git",
+			ownerName: "airplanedev",
+			repoName:  "airport",
+			vendor:    api.GitVendorGitLab,
+		},
+		{
+			desc:      "gitlab ssh with nested groups",
+			remote:    "git@gitlab.com:airplanedev/infra/tools/airport.git",
+			ownerName: "airplanedev/infra/tools",
+			repoName:  "airport",
+			vendor:    api.GitVendorGitLab,
+		},
+		{
+			desc:      "gitlab ssh url with port",
+			remote:    "ssh://git@gitlab.com:2222/airplanedev/infra/airport.git",
+			ownerName: "airplanedev/infra",
+			repoName:  "airport",
+			vendor:    api.GitVendorGitLab,
+		},
+		{
+			desc:      "bitbucket http with user",
+			remote:    "https://jdoe@bitbucket.org/airplanedev/airport.git",
+			ownerName: "airplanedev",
+			repoName:  "airport",
+			vendor:    api.GitVendorBitbucket,
+		},
+		{
+			desc:      "bitbucket ssh",
+			remote:    "git@bitbucket.org:airplanedev/airport.git",
+			ownerName: "airplanedev",
+			repoName:  "airport",
+			vendor:    api.GitVendorBitbucket,
+		},
+		{
+			desc:      "azure devops http",
+			remote:    "https://airplanedev@dev.azure.com/airplanedev/platform/_git/airport",
+			ownerName: "airplanedev/platform",
+			repoName:  "airport",
+			vendor:    api.GitVendorAzureDevOps,
+		},
+		{
+			desc:      "azure devops ssh",
+			remote:    "git@ssh.dev.azure.com:v3/airplanedev/platform/airport",
+			ownerName: "airplanedev/platform",
+			repoName:  "airport",
+			vendor:    api.GitVendorAzureDevOps,
+		},
+		{
+			desc:      "azure devops visualstudio.com",
+			remote:    "https://airplanedev.visualstudio.com/DefaultCollection/platform/_git/airport",
+			ownerName: "airplanedev/platform",
+			repoName:  "airport",
+			vendor:    api.GitVendorAzureDevOps,
+		},
+		{
+			desc:      "self-hosted with vendor mapping",
+			remote:    "git@git.example.com:airplanedev/airport.git",
+			vendors:   map[string]api.GitVendor{"git.example.com": api.GitVendorGitea},
+			ownerName: "airplanedev",
+			repoName:  "airport",
+			vendor:    api.GitVendorGitea,
+		},
+		{
+			desc:      "self-hosted without vendor mapping",
+			remote:    "https://git.example.com/airplanedev/airport.git",
+			ownerName: "airplanedev",
+			repoName:  "airport",
+		},
+		{
+			desc:      "github enterprise mapped to github",
+			remote:    "https://GitHub.Example.com/airplanedev/airport",
+			vendors:   map[string]api.GitVendor{"github.example.com": api.GitVendorGitHub},
+			ownerName: "airplanedev",
+			repoName:  "airport",
+			vendor:    api.GitVendorGitHub,
+		},
+		{
+			desc:    "missing repo",
+			remote:  "https://gitlab.com/airplanedev",
+			wantErr: true,
+		},
+		{
+			desc:    "azure devops without _git",
+			remote:  "https://dev.azure.com/airplanedev/platform/airport",
+			wantErr: true,
+		},
+		{
+			desc:   "local path - no error returned",
+			remote: "/srv/git/airport.git",
+		},
+		{
+			desc:   "windows path - no error returned",
+			remote: `C:\src\airport`,
+		},
 		{
 			desc:   "unknown - no error returned",
 			remote: "some remote",
@@ -816,7 +1008,11 @@ func TestParseRemote(t *testing.T) {
 			assert := assert.New(t)
 			require := require.New(t)
 
-			owner, name, vendor, err := parseRemote(tC.remote)
+			owner, name, vendor, err := parseRemote(tC.remote, tC.vendors)
+			if tC.wantErr {
+				require.Error(err)
+				return
+			}
 			require.NoError(err)
 
 			assert.Equal(tC.ownerName, owner)
@@ -933,3 +1129,275 @@ func TestGetDefinitionDiff(t *testing.T) {
 		})
 	}
 }
+
+func TestGetTaskPlan(t *testing.T) {
+	existingTasks := map[string]libapi.Task{
+		"my_task": {
+			ID:        "my_task",
+			Slug:      "my_task",
+			Name:      "My Task",
+			Kind:      "image",
+			Image:     pointers.String("ubuntu:latest"),
+			Arguments: []string{"echo", "hello world"},
+		},
+	}
+	for _, test := range []struct {
+		name          string
+		description   string
+		existingTasks map[string]libapi.Task
+		expected      PlanItem
+	}{
+		{
+			name:          "unchanged",
+			existingTasks: existingTasks,
+			expected:      PlanItem{Slug: "my_task", Kind: "image", Status: PlanStatusUnchanged},
+		},
+		{
+			name:          "changed",
+			description:   "Says hello!",
+			existingTasks: existingTasks,
+			expected: PlanItem{Slug: "my_task", Kind: "image", Status: PlanStatusChanged, Changes: []utils.FieldChange{
+				{Path: "description", New: "Says hello!"},
+			}},
+		},
+		{
+			name:          "new environment",
+			existingTasks: map[string]libapi.Task{},
+			expected:      PlanItem{Slug: "my_task", Kind: "image", Status: PlanStatusNew},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			cfg := config{
+				client: &api.MockClient{
+					Tasks: test.existingTasks,
+				},
+			}
+			d := NewDeployer(cfg, &logger.MockLogger{}, DeployerOpts{})
+			item, err := d.getTaskPlan(context.Background(), discover.TaskConfig{
+				TaskID: "my_task",
+				Def: &definitions.Definition_0_3{
+					Name:        "My Task",
+					Description: test.description,
+					Slug:        "my_task",
+					Image: &definitions.ImageDefinition_0_3{
+						Image:   "ubuntu:latest",
+						Command: "echo 'hello world'",
+					},
+				},
+			}, false)
+			require.NoError(err)
+			require.Equal(test.expected, item)
+		})
+	}
+}
+
+func TestGetViewDefinitionDiff(t *testing.T) {
+	viewConfig := discover.ViewConfig{
+		ID: "view123",
+		Def: definitions.ViewDefinition{
+			Slug:        "my_view",
+			Name:        "My View",
+			Description: "Shows things",
+		},
+	}
+	for _, test := range []struct {
+		name          string
+		isNew         bool
+		existingViews map[string]libapi.View
+		expected      []string
+	}{
+		{
+			name:     "new view",
+			isNew:    true,
+			expected: []string{"(new view)"},
+		},
+		{
+			name: "no changes",
+			existingViews: map[string]libapi.View{
+				"my_view": {ID: "view123", Slug: "my_view", Name: "My View", Description: "Shows things"},
+			},
+			expected: []string{"(no changes to view definition)"},
+		},
+		{
+			name: "show diff",
+			existingViews: map[string]libapi.View{
+				"my_view": {ID: "view123", Slug: "my_view", Name: "My View"},
+			},
+			expected: []string{
+				"--- a/my_view",
+				"+++ b/my_view",
+				"@@ -1,2 +1,3 @@",
+				" slug: my_view",
+				" name: My View",
+				"+description: Shows things",
+				"",
+			},
+		},
+		{
+			name:          "deploy view into new environment",
+			existingViews: map[string]libapi.View{},
+			expected:      []string{"(view created in new environment)"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+
+			cfg := config{
+				client: &api.MockClient{
+					Views: test.existingViews,
+				},
+			}
+			d := NewDeployer(cfg, &logger.MockLogger{}, DeployerOpts{})
+			diff, err := d.getViewDefinitionDiff(context.Background(), viewConfig, test.isNew)
+			require.NoError(err)
+			require.Equal(test.expected, diff)
+		})
+	}
+}
+
+func TestRootHasChanges(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		root     string
+		files    []string
+		expected bool
+	}{
+		{name: "file in root", root: "/repo/tasks/a", files: []string{"/repo/tasks/a/index.ts"}, expected: true},
+		{name: "file in subdirectory", root: "/repo/tasks/a", files: []string{"/repo/tasks/a/lib/util.ts"}, expected: true},
+		{name: "file in sibling with shared prefix", root: "/repo/tasks/a", files: []string{"/repo/tasks/ab/index.ts"}, expected: false},
+		{name: "lockfile in parent", root: "/repo/tasks/a", files: []string{"/repo/yarn.lock"}, expected: true},
+		{name: "package.json in parent", root: "/repo/tasks/a", files: []string{"/repo/tasks/package.json"}, expected: true},
+		{name: "package.json in sibling", root: "/repo/tasks/a", files: []string{"/repo/tasks/b/package.json"}, expected: false},
+		{name: "other file in parent", root: "/repo/tasks/a", files: []string{"/repo/README.md"}, expected: false},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			changed, err := rootHasChanges(filepath.FromSlash(test.root), test.files)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, changed)
+		})
+	}
+}
+
+func TestGetChangedFilesSince(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(err)
+	w, err := repo.Worktree()
+	require.NoError(err)
+
+	write := func(path, content string) {
+		p := filepath.Join(dir, path)
+		require.NoError(os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(os.WriteFile(p, []byte(content), 0644))
+		_, err := w.Add(path)
+		require.NoError(err)
+	}
+	commit := func() {
+		_, err := w.Commit("commit", &git.CommitOptions{
+			Author: &object.Signature{Name: "test", Email: "test@airplane.dev", When: time.Now()},
+		})
+		require.NoError(err)
+	}
+
+	write("a/index.ts", "a")
+	write("b/index.ts", "b")
+	commit()
+	head, err := repo.Head()
+	require.NoError(err)
+
+	// Committed, staged and untracked changes are all included.
+	write("a/index.ts", "a2")
+	commit()
+	write("b/index.ts", "b2")
+	require.NoError(os.WriteFile(filepath.Join(dir, "c.ts"), []byte("c"), 0644))
+
+	files, err := getChangedFilesSince(repo, head.Hash().String())
+	require.NoError(err)
+	require.Equal([]string{
+		filepath.Join(dir, "a", "index.ts"),
+		filepath.Join(dir, "b", "index.ts"),
+		filepath.Join(dir, "c.ts"),
+	}, files)
+}
+
+func TestGetChangedFilesSinceDivergedBranch(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(err)
+	w, err := repo.Worktree()
+	require.NoError(err)
+
+	write := func(path, content string) {
+		p := filepath.Join(dir, path)
+		require.NoError(os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(os.WriteFile(p, []byte(content), 0644))
+		_, err := w.Add(path)
+		require.NoError(err)
+	}
+	commit := func() {
+		_, err := w.Commit("commit", &git.CommitOptions{
+			Author: &object.Signature{Name: "test", Email: "test@airplane.dev", When: time.Now()},
+		})
+		require.NoError(err)
+	}
+
+	write("a/index.ts", "a")
+	write("b/index.ts", "b")
+	commit()
+	head, err := repo.Head()
+	require.NoError(err)
+
+	// main moves on after the feature branch is created.
+	main := plumbing.NewBranchReferenceName("main")
+	require.NoError(w.Checkout(&git.CheckoutOptions{Branch: main, Create: true}))
+	write("b/index.ts", "b2")
+	commit()
+
+	require.NoError(w.Checkout(&git.CheckoutOptions{Branch: head.Name()}))
+	write("a/index.ts", "a2")
+	commit()
+
+	// Only the changes of the feature branch are included, not the ones on main.
+	files, err := getChangedFilesSince(repo, "main")
+	require.NoError(err)
+	require.Equal([]string{filepath.Join(dir, "a", "index.ts")}, files)
+}
+
+func TestGetChangedFilesSinceRepoOfPaths(t *testing.T) {
+	require := require.New(t)
+
+	// Two repos, neither of which contains the working directory.
+	var dirs []string
+	for i := 0; i < 2; i++ {
+		dir := t.TempDir()
+		repo, err := git.PlainInit(dir, false)
+		require.NoError(err)
+		w, err := repo.Worktree()
+		require.NoError(err)
+		require.NoError(os.MkdirAll(filepath.Join(dir, "task"), 0755))
+		require.NoError(os.WriteFile(filepath.Join(dir, "task", "index.ts"), []byte("a"), 0644))
+		_, err = w.Add("task/index.ts")
+		require.NoError(err)
+		_, err = w.Commit("commit", &git.CommitOptions{
+			Author: &object.Signature{Name: "test", Email: "test@airplane.dev", When: time.Now()},
+		})
+		require.NoError(err)
+		require.NoError(os.WriteFile(filepath.Join(dir, "task", "index.ts"), []byte("b"), 0644))
+		dirs = append(dirs, dir)
+	}
+
+	files, err := GetChangedFilesSince([]string{
+		filepath.Join(dirs[0], "task"),
+		filepath.Join(dirs[0], "task", "index.ts"),
+		dirs[1],
+	}, "HEAD")
+	require.NoError(err)
+	require.Equal([]string{
+		filepath.Join(dirs[0], "task", "index.ts"),
+		filepath.Join(dirs[1], "task", "index.ts"),
+	}, files)
+}
@@ -1,13 +1,20 @@
 package deploy
 
 import (
+	"net/url"
+	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
 
 	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/conf"
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/pkg/errors"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 type GitRepoGetter interface {
@@ -47,6 +54,113 @@ func GetEntrypointRelativeToGitRoot(repo *git.Repository, taskFilePath string) (
 	return filepath.Rel(w.Filesystem.Root(), taskFilePath)
 }
 
+// GetChangedFilesSince returns the absolute paths of the files that changed between the merge
+// base of ref and HEAD, and the worktree, of the git repos that contain paths. Uncommitted and
+// untracked files are included. Paths in the same repo are only diffed once.
+func GetChangedFilesSince(paths []string, ref string) ([]string, error) {
+	roots := map[string]bool{}
+	var files []string
+	for _, path := range paths {
+		dir := path
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			dir = filepath.Dir(path)
+		}
+		repo, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{
+			DetectDotGit: true,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "opening git repo for %s", path)
+		}
+		w, err := repo.Worktree()
+		if err != nil {
+			return nil, err
+		}
+		root := w.Filesystem.Root()
+		if roots[root] {
+			continue
+		}
+		roots[root] = true
+		changed, err := getChangedFilesSince(repo, ref)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, changed...)
+	}
+	return files, nil
+}
+
+func getChangedFilesSince(repo *git.Repository, ref string) ([]string, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return nil, errors.Wrapf(err, "resolving %s", ref)
+	}
+	refCommit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting commit %s", ref)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting HEAD")
+	}
+	to, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, errors.Wrap(err, "getting HEAD commit")
+	}
+	// Like `git diff ref...HEAD`, diff against the merge base, so that commits on ref that are not
+	// on HEAD, e.g. on the main branch since a feature branch was created, are not included.
+	bases, err := to.MergeBase(refCommit)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding merge base of %s and HEAD", ref)
+	}
+	if len(bases) == 0 {
+		return nil, errors.Errorf("%s and HEAD have no common ancestor", ref)
+	}
+	from := bases[0]
+	fromTree, err := from.Tree()
+	if err != nil {
+		return nil, err
+	}
+	toTree, err := to.Tree()
+	if err != nil {
+		return nil, err
+	}
+	changes, err := object.DiffTree(fromTree, toTree)
+	if err != nil {
+		return nil, errors.Wrapf(err, "diffing %s and HEAD", ref)
+	}
+
+	w, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	root := w.Filesystem.Root()
+	files := map[string]bool{}
+	add := func(path string) {
+		if path != "" {
+			files[filepath.Join(root, filepath.FromSlash(path))] = true
+		}
+	}
+	// Both names are included so that renames count as changes to both directories.
+	for _, c := range changes {
+		add(c.From.Name)
+		add(c.To.Name)
+	}
+
+	status, err := w.Status()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting worktree status")
+	}
+	for path, s := range status {
+		if s.Staging != git.Unmodified || s.Worktree != git.Unmodified {
+			add(path)
+		}
+	}
+
+	paths := maps.Keys(files)
+	slices.Sort(paths)
+	return paths, nil
+}
+
 func GetGitMetadata(repo *git.Repository) (api.GitMetadata, error) {
 	meta := api.GitMetadata{}
 
@@ -103,7 +217,7 @@ func GetGitMetadata(repo *git.Repository) (api.GitMetadata, error) {
 		// URLs will always be non-empty. Use the first URL which is used
 		// by git for fetching from a remote.
 		remoteURL := remote.Config().URLs[0]
-		repoOwner, repoName, vendor, err := parseRemote(remoteURL)
+		repoOwner, repoName, vendor, err := parseRemote(remoteURL, getGitVendors())
 		if err != nil {
 			return meta, errors.Wrapf(err, "parsing remote %s", remote.Config().URLs[0])
 		}
@@ -116,25 +230,129 @@ func GetGitMetadata(repo *git.Repository) (api.GitMetadata, error) {
 }
 
 var (
-	githubHTTPRegex, _ = regexp.Compile(`^https:\/\/github\.com\/(.+)\/(.+?)(\.git)?$`)
-	githubSSHRegex, _  = regexp.Compile(`^git@github\.com:(.+)\/(.+?)(\.git)?$`)
+	// scpLikeRemoteRegex matches scp-like remotes, e.g. git@github.com:airplanedev/cli.git.
+	scpLikeRemoteRegex = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):(.+)$`)
 )
 
-func parseRemote(remote string) (repoOwner, repoName string, vendor api.GitVendor, err error) {
-	switch {
-	case githubHTTPRegex.MatchString(remote):
-		matches := githubHTTPRegex.FindStringSubmatch(remote)
-		if len(matches) < 3 {
-			return "", "", "", errors.Errorf("invalid github http remote %s", remote)
-		}
-		return matches[1], matches[2], api.GitVendorGitHub, nil
-	case githubSSHRegex.MatchString(remote):
-		matches := githubSSHRegex.FindStringSubmatch(remote)
-		if len(matches) < 3 {
-			return "", "", "", errors.Errorf("invalid github ssh remote %s", remote)
+// knownGitHosts maps the hosts of hosted git vendors to the vendor.
+var knownGitHosts = map[string]api.GitVendor{
+	"github.com":              api.GitVendorGitHub,
+	"gitlab.com":              api.GitVendorGitLab,
+	"bitbucket.org":           api.GitVendorBitbucket,
+	"dev.azure.com":           api.GitVendorAzureDevOps,
+	"ssh.dev.azure.com":       api.GitVendorAzureDevOps,
+	"vs-ssh.visualstudio.com": api.GitVendorAzureDevOps,
+}
+
+// getGitVendors returns the host to vendor mapping configured through AP_GIT_VENDORS, so that
+// remotes on self-hosted instances are attributed to the right vendor.
+func getGitVendors() map[string]api.GitVendor {
+	vendors := map[string]api.GitVendor{}
+	for host, name := range conf.GetGitVendors() {
+		vendor := api.GitVendor(name)
+		for _, v := range api.GitVendors {
+			if strings.EqualFold(name, string(v)) {
+				vendor = v
+			}
 		}
-		return matches[1], matches[2], api.GitVendorGitHub, nil
-	default:
+		vendors[strings.ToLower(host)] = vendor
+	}
+	return vendors
+}
+
+// parseRemote returns the repository owner, name and vendor of a git remote. HTTPS, SSH and
+// scp-like remotes are supported on any host. The vendor is looked up in vendors first and then
+// in the list of hosted vendors; it is empty if the host is not recognized. Remotes that are not
+// URLs, e.g. local paths, are ignored.
+func parseRemote(remote string, vendors map[string]api.GitVendor) (repoOwner, repoName string, vendor api.GitVendor, err error) {
+	host, path := splitRemote(remote)
+	if host == "" {
 		return "", "", "", nil
 	}
+	vendor = getGitVendor(host, vendors)
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	var segments []string
+	for _, s := range strings.Split(path, "/") {
+		if s != "" {
+			segments = append(segments, s)
+		}
+	}
+
+	if vendor == api.GitVendorAzureDevOps {
+		repoOwner, repoName = parseAzureDevOpsPath(host, segments)
+		if repoName == "" {
+			return "", "", "", errors.Errorf("invalid azure devops remote %s", remote)
+		}
+		return repoOwner, repoName, vendor, nil
+	}
+
+	// GitLab supports nested groups, so everything but the last segment is the owner.
+	if len(segments) < 2 {
+		return "", "", "", errors.Errorf("invalid remote %s: expected <owner>/<repo>", remote)
+	}
+	n := len(segments)
+	return strings.Join(segments[:n-1], "/"), segments[n-1], vendor, nil
+}
+
+// splitRemote returns the host and path of a remote. The host is empty if the remote is not a
+// URL or an scp-like address.
+func splitRemote(remote string) (host, path string) {
+	if strings.Contains(remote, "://") {
+		u, err := url.Parse(remote)
+		if err != nil {
+			return "", ""
+		}
+		return strings.ToLower(u.Hostname()), u.Path
+	}
+	matches := scpLikeRemoteRegex.FindStringSubmatch(remote)
+	// Single letter hosts are Windows drive letters, e.g. C:\repo.
+	if matches == nil || len(matches[1]) == 1 {
+		return "", ""
+	}
+	return strings.ToLower(matches[1]), matches[2]
+}
+
+func getGitVendor(host string, vendors map[string]api.GitVendor) api.GitVendor {
+	if v, ok := vendors[host]; ok {
+		return v
+	}
+	if v, ok := knownGitHosts[host]; ok {
+		return v
+	}
+	if strings.HasSuffix(host, ".visualstudio.com") {
+		return api.GitVendorAzureDevOps
+	}
+	return ""
+}
+
+// parseAzureDevOpsPath returns the owner and name of an Azure DevOps repo. The owner is
+// <organization>/<project>. Remotes look like:
+//
+//	https://dev.azure.com/<org>/<project>/_git/<repo>
+//	https://<org>.visualstudio.com/[DefaultCollection/]<project>/_git/<repo>
+//	git@ssh.dev.azure.com:v3/<org>/<project>/<repo>
+func parseAzureDevOpsPath(host string, segments []string) (owner, name string) {
+	if len(segments) == 4 && segments[0] == "v3" {
+		return segments[1] + "/" + segments[2], segments[3]
+	}
+	i := slices.Index(segments, "_git")
+	if i < 0 || i != len(segments)-2 {
+		return "", ""
+	}
+	name = segments[i+1]
+	segments = segments[:i]
+	if strings.HasSuffix(host, ".visualstudio.com") {
+		owner := []string{strings.TrimSuffix(host, ".visualstudio.com")}
+		for _, s := range segments {
+			if s != "DefaultCollection" {
+				owner = append(owner, s)
+			}
+		}
+		segments = owner
+	}
+	if len(segments) != 2 {
+		return "", ""
+	}
+	return segments[0] + "/" + segments[1], name
 }
@@ -0,0 +1,222 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/airplanedev/cli/pkg/build"
+	"github.com/airplanedev/cli/pkg/logger"
+	libBuild "github.com/airplanedev/lib/pkg/build"
+	"github.com/airplanedev/lib/pkg/deploy/discover"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+)
+
+// localBuild tracks the progress of building a single task image with --local.
+type localBuild struct {
+	tc  discover.TaskConfig
+	log *buildLog
+
+	queuedAt   time.Time
+	startedAt  time.Time
+	pushedAt   time.Time
+	finishedAt time.Time
+
+	imageURL string
+	err      error
+}
+
+// buildLocalImages builds the images of the tasks that need building with a local Docker
+// daemon, at most cfg.buildConcurrency at a time. The output of each build is captured and only
+// printed if the build fails. It returns the image URL of each task, keyed by slug.
+func (d *deployer) buildLocalImages(ctx context.Context, taskConfigs []discover.TaskConfig) (map[string]string, error) {
+	var builds []*localBuild
+	for _, tc := range taskConfigs {
+		kind, _, err := tc.Def.GetKindAndOptions()
+		if err != nil {
+			return nil, err
+		}
+		if ok, err := libBuild.NeedsBuilding(kind); err != nil {
+			return nil, err
+		} else if ok {
+			builds = append(builds, &localBuild{tc: tc, log: &buildLog{}})
+		}
+	}
+	if len(builds) == 0 {
+		return nil, nil
+	}
+
+	concurrency := d.cfg.buildConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	d.logger.Log("Building %d %s locally with a concurrency of %d...", len(builds), pluralize(len(builds), "image", "images"), concurrency)
+
+	// Failed builds do not cancel the others, so that every failure is reported at once.
+	var g errgroup.Group
+	g.SetLimit(concurrency)
+	for _, b := range builds {
+		b := b
+		b.queuedAt = time.Now()
+		d.logBuildStatus(b, "queued")
+		g.Go(func() error {
+			d.runLocalBuild(ctx, b)
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	d.printBuildSummary(builds)
+
+	images := map[string]string{}
+	var failed int
+	for _, b := range builds {
+		if b.err != nil {
+			failed++
+			d.logger.Log("")
+			d.logger.Error("Failed to build %s: %v", b.tc.Def.GetSlug(), b.err)
+			for _, line := range b.log.lines {
+				d.logger.Log("  %s", logger.Gray("%s", line))
+			}
+			continue
+		}
+		images[b.tc.Def.GetSlug()] = b.imageURL
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if failed > 0 {
+		return nil, errors.Errorf("%d %s failed", failed, pluralize(failed, "build", "builds"))
+	}
+	return images, nil
+}
+
+func (d *deployer) runLocalBuild(ctx context.Context, b *localBuild) {
+	env, err := b.tc.Def.GetEnv()
+	if err != nil {
+		b.err = err
+		b.finishedAt = time.Now()
+		d.logBuildStatus(b, "failed")
+		return
+	}
+
+	resp, err := d.buildCreator.CreateBuild(ctx, build.Request{
+		Client:  d.cfg.client,
+		TaskID:  b.tc.TaskID,
+		Root:    b.tc.TaskRoot,
+		Def:     b.tc.Def,
+		TaskEnv: env,
+		Shim:    true,
+		Logger:  b.log,
+		OnStatus: func(status build.Status) {
+			switch status {
+			case build.StatusBuilding:
+				b.startedAt = time.Now()
+				d.logBuildStatus(b, "building")
+			case build.StatusPushing:
+				b.pushedAt = time.Now()
+				d.logBuildStatus(b, fmt.Sprintf("pushing (built in %s)", formatBuildDuration(b.pushedAt.Sub(b.startedAt))))
+			}
+		},
+	})
+	b.finishedAt = time.Now()
+	if err != nil {
+		b.err = err
+		d.logBuildStatus(b, fmt.Sprintf("failed after %s", formatBuildDuration(b.finishedAt.Sub(b.queuedAt))))
+		return
+	}
+	b.imageURL = resp.ImageURL
+	d.logBuildStatus(b, fmt.Sprintf("done in %s", formatBuildDuration(b.finishedAt.Sub(b.queuedAt))))
+}
+
+func (d *deployer) logBuildStatus(b *localBuild, status string) {
+	d.logger.Log("%s %s", logger.Bold(b.tc.Def.GetSlug()), logger.Gray("%s", status))
+}
+
+// printBuildSummary prints a table of how long each stage of each build took.
+func (d *deployer) printBuildSummary(builds []*localBuild) {
+	d.logger.Log("")
+	tw := tablewriter.NewWriter(os.Stderr)
+	tw.SetBorder(false)
+	tw.SetAutoFormatHeaders(false)
+	tw.SetHeader([]string{"task", "status", "queued", "build", "push", "total"})
+	for _, b := range builds {
+		status := "done"
+		if b.err != nil {
+			status = "failed"
+		}
+		tw.Append([]string{
+			b.tc.Def.GetSlug(),
+			status,
+			stageDuration(b.queuedAt, b.startedAt),
+			stageDuration(b.startedAt, b.pushedAt),
+			stageDuration(b.pushedAt, b.finishedAt),
+			stageDuration(b.queuedAt, b.finishedAt),
+		})
+	}
+	tw.Render()
+}
+
+// stageDuration formats the duration of a stage, or "-" if the stage was never reached.
+func stageDuration(start, end time.Time) string {
+	if start.IsZero() || end.IsZero() {
+		return "-"
+	}
+	return formatBuildDuration(end.Sub(start))
+}
+
+func formatBuildDuration(d time.Duration) string {
+	return d.Round(100 * time.Millisecond).String()
+}
+
+// buildLog captures the messages of a single build, so that the messages of concurrent builds
+// does not interleave.
+type buildLog struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+var _ logger.Logger = &buildLog{}
+
+func (l *buildLog) add(msg string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, fmt.Sprintf(msg, args...))
+}
+
+func (l *buildLog) Log(msg string, args ...interface{}) {
+	l.add(msg, args...)
+}
+
+func (l *buildLog) Warning(msg string, args ...interface{}) {
+	l.add("[warning] "+msg, args...)
+}
+
+func (l *buildLog) Error(msg string, args ...interface{}) {
+	l.add("[error] "+msg, args...)
+}
+
+func (l *buildLog) Debug(msg string, args ...interface{}) {
+	if logger.EnableDebug {
+		l.add("[debug] "+msg, args...)
+	}
+}
+
+func (l *buildLog) Step(msg string, args ...interface{}) {
+	l.add(msg, args...)
+}
+
+func (l *buildLog) Suggest(title, command string, args ...interface{}) {
+	l.add("%s %s", title, fmt.Sprintf(command, args...))
+}
+
+func (l *buildLog) SuggestSteps(title string, steps ...string) {
+	l.add("%s", title)
+	for _, step := range steps {
+		l.add("  %s", step)
+	}
+}
@@ -0,0 +1,154 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/build"
+	"github.com/airplanedev/cli/pkg/logger"
+	libBuild "github.com/airplanedev/lib/pkg/build"
+	"github.com/airplanedev/lib/pkg/deploy/discover"
+	"github.com/airplanedev/lib/pkg/deploy/taskdir/definitions"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBuildCreator builds every task after a short delay and tracks how many builds run at once.
+// Builds of the tasks in fail fail after logging some output.
+type fakeBuildCreator struct {
+	fail map[string]bool
+
+	mu        sync.Mutex
+	active    int
+	maxActive int
+}
+
+func (c *fakeBuildCreator) CreateBuild(ctx context.Context, req build.Request) (*libBuild.Response, error) {
+	c.mu.Lock()
+	c.active++
+	if c.active > c.maxActive {
+		c.maxActive = c.active
+	}
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		c.active--
+		c.mu.Unlock()
+	}()
+
+	slug := req.Def.GetSlug()
+	req.OnStatus(build.StatusBuilding)
+	req.Logger.Log("Step 1/2 : FROM node:16")
+	time.Sleep(20 * time.Millisecond)
+	if c.fail[slug] {
+		req.Logger.Log("npm ERR! missing script: build")
+		return nil, errors.New("build: exit code 1")
+	}
+	req.OnStatus(build.StatusPushing)
+	return &libBuild.Response{ImageURL: "image/" + slug}, nil
+}
+
+// recordingLogger records the lines that are logged.
+type recordingLogger struct {
+	logger.MockLogger
+
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *recordingLogger) Log(msg string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, fmt.Sprintf(msg, args...))
+}
+
+func (l *recordingLogger) Error(msg string, args ...interface{}) {
+	l.Log("[error] "+msg, args...)
+}
+
+func TestBuildLocalImages(t *testing.T) {
+	require := require.New(t)
+
+	var taskConfigs []discover.TaskConfig
+	for i := 0; i < 5; i++ {
+		taskConfigs = append(taskConfigs, discover.TaskConfig{
+			TaskID: fmt.Sprintf("tsk%d", i),
+			Def: &definitions.Definition_0_3{
+				Slug: fmt.Sprintf("task_%d", i),
+				Node: &definitions.NodeDefinition_0_3{},
+			},
+		})
+	}
+	// SQL tasks are not built.
+	taskConfigs = append(taskConfigs, discover.TaskConfig{
+		TaskID: "tsk_sql",
+		Def: &definitions.Definition_0_3{
+			Slug: "sql_task",
+			SQL:  &definitions.SQLDefinition_0_3{},
+		},
+	})
+
+	t.Run("builds with a concurrency limit", func(t *testing.T) {
+		bc := &fakeBuildCreator{}
+		l := &recordingLogger{}
+		d := NewDeployer(config{client: &api.MockClient{}, local: true, buildConcurrency: 2}, l, DeployerOpts{
+			BuildCreator: bc,
+		})
+		images, err := d.buildLocalImages(context.Background(), taskConfigs)
+		require.NoError(err)
+		require.Len(images, 5)
+		require.Equal("image/task_0", images["task_0"])
+		require.Equal(2, bc.maxActive)
+
+		// Each build moves through its stages in order.
+		for i := 0; i < 5; i++ {
+			var statuses []string
+			for _, line := range l.lines {
+				if strings.HasPrefix(stripANSI(line), fmt.Sprintf("task_%d ", i)) {
+					statuses = append(statuses, strings.Fields(stripANSI(line))[1])
+				}
+			}
+			require.Equal([]string{"queued", "building", "pushing", "done"}, statuses)
+		}
+		// The output of successful builds is not printed.
+		for _, line := range l.lines {
+			require.NotContains(line, "Step 1/2")
+		}
+	})
+
+	t.Run("summarizes failures", func(t *testing.T) {
+		bc := &fakeBuildCreator{fail: map[string]bool{"task_1": true, "task_3": true}}
+		l := &recordingLogger{}
+		d := NewDeployer(config{client: &api.MockClient{}, local: true, buildConcurrency: 4}, l, DeployerOpts{
+			BuildCreator: bc,
+		})
+		_, err := d.buildLocalImages(context.Background(), taskConfigs)
+		require.EqualError(err, "2 builds failed")
+
+		// Failed builds print their captured output, in order.
+		output := stripANSI(strings.Join(l.lines, "\n"))
+		require.Contains(output, "[error] Failed to build task_1: build: exit code 1\n  Step 1/2 : FROM node:16\n  npm ERR! missing script: build")
+		require.Contains(output, "[error] Failed to build task_3: build: exit code 1")
+		require.NotContains(output, "Failed to build task_0")
+		require.Less(strings.Index(output, "Failed to build task_1"), strings.Index(output, "Failed to build task_3"))
+	})
+}
+
+func stripANSI(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\x1b' {
+			for i < len(s) && s[i] != 'm' {
+				i++
+			}
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,159 @@
+package deploy
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/airplanedev/lib/pkg/deploy/discover"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+)
+
+// envDeployResult is the result of deploying to one of several environments.
+type envDeployResult struct {
+	envSlug      string
+	deploymentID string
+	duration     time.Duration
+	err          error
+	// skipped is true if the environment was not deployed to because a previous environment
+	// failed and --stop-on-failure is set.
+	skipped bool
+}
+
+// envSlugs returns the environments to deploy to, in order.
+func (d *deployer) envSlugs() []string {
+	if len(d.cfg.envSlugs) > 0 {
+		return d.cfg.envSlugs
+	}
+	return []string{d.cfg.envSlug}
+}
+
+// forEnv returns a copy of the deployer that deploys to the given environment.
+func (d *deployer) forEnv(envSlug string) *deployer {
+	e := *d
+	e.cfg.envSlug = envSlug
+	e.cfg.envSlugs = nil
+	return &e
+}
+
+// deployToEnvs deploys the same configs to several environments. Configs are checked in every
+// environment and built once, then deployed to each environment in order after a single
+// confirmation.
+func (d *deployer) deployToEnvs(ctx context.Context, taskConfigs []discover.TaskConfig, viewConfigs []discover.ViewConfig, createdTasks, createdViews map[string]bool) error {
+	envSlugs := d.envSlugs()
+	for _, envSlug := range envSlugs {
+		if err := d.forEnv(envSlug).checkConfigVars(ctx, taskConfigs); err != nil {
+			return errors.Wrapf(err, "checking configs in %s", EnvName(envSlug))
+		}
+	}
+
+	var hasDiff bool
+	for _, envSlug := range envSlugs {
+		d.logger.Log(logger.Bold("Environment: %s\n", EnvName(envSlug)))
+		envHasDiff, err := d.forEnv(envSlug).printDeploySummary(ctx, taskConfigs, viewConfigs, createdTasks, createdViews)
+		if err != nil {
+			return err
+		}
+		hasDiff = hasDiff || envHasDiff
+	}
+	if hasDiff {
+		if err := d.confirmDeployment(ctx); err != nil {
+			if err == skippedDeployErr {
+				return nil
+			}
+			return err
+		}
+	}
+
+	uploadIDs, images, err := d.build(ctx, taskConfigs, viewConfigs)
+	if err != nil {
+		return err
+	}
+
+	results := make([]envDeployResult, len(envSlugs))
+	var failed []string
+	for i, envSlug := range envSlugs {
+		results[i].envSlug = envSlug
+		if len(failed) > 0 && d.cfg.stopOnFailure {
+			results[i].skipped = true
+			continue
+		}
+
+		d.logger.Log(logger.Bold("Deploying to %s...", EnvName(envSlug)))
+		start := time.Now()
+		results[i].deploymentID, results[i].err = d.forEnv(envSlug).createDeployment(ctx, taskConfigs, viewConfigs, uploadIDs, images)
+		results[i].duration = time.Since(start)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if results[i].err != nil {
+			d.logger.Error("Failed to deploy to %s: %v", EnvName(envSlug), results[i].err)
+			failed = append(failed, EnvName(envSlug))
+		}
+	}
+
+	d.printEnvDeploySummary(results)
+	if len(failed) > 0 {
+		return errors.Errorf("deploy failed in %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// printEnvDeploySummary prints a table of the result of deploying to each environment.
+func (d *deployer) printEnvDeploySummary(results []envDeployResult) {
+	d.logger.Log("")
+	tw := tablewriter.NewWriter(os.Stderr)
+	tw.SetBorder(false)
+	tw.SetAutoFormatHeaders(false)
+	tw.SetHeader([]string{"environment", "status", "deployment", "duration"})
+	for _, r := range results {
+		status, duration := "succeeded", r.duration.Round(time.Second).String()
+		switch {
+		case r.skipped:
+			status, duration = "skipped", "-"
+		case r.err != nil:
+			status = "failed"
+		}
+		deploymentID := r.deploymentID
+		if deploymentID == "" {
+			deploymentID = "-"
+		}
+		tw.Append([]string{EnvName(r.envSlug), status, deploymentID, duration})
+	}
+	tw.Render()
+}
+
+// parseEnvSlugs splits a comma-separated --env into environments, dropping duplicates.
+func parseEnvSlugs(cfg *config) error {
+	seen := map[string]bool{}
+	var envSlugs []string
+	for _, envSlug := range cfg.envSlugs {
+		envSlug = strings.TrimSpace(envSlug)
+		if envSlug == "" || seen[envSlug] {
+			continue
+		}
+		seen[envSlug] = true
+		envSlugs = append(envSlugs, envSlug)
+	}
+	if len(envSlugs) == 0 {
+		cfg.envSlugs = nil
+		return nil
+	}
+	cfg.envSlug = envSlugs[0]
+	if len(envSlugs) == 1 {
+		cfg.envSlugs = nil
+		return nil
+	}
+	cfg.envSlugs = envSlugs
+
+	switch {
+	case cfg.noWait:
+		return errors.New("--no-wait cannot be used when deploying to multiple environments")
+	case len(cfg.reports) > 0:
+		return errors.New("--report cannot be used when deploying to multiple environments")
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package deploy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEnvSlugs(t *testing.T) {
+	for _, test := range []struct {
+		name        string
+		cfg         config
+		envSlug     string
+		envSlugs    []string
+		expectedErr bool
+	}{
+		{
+			name: "default environment",
+		},
+		{
+			name:    "single environment",
+			cfg:     config{envSlugs: []string{"prod"}},
+			envSlug: "prod",
+		},
+		{
+			name:     "multiple environments",
+			cfg:      config{envSlugs: []string{"staging", " prod", "staging", ""}},
+			envSlug:  "staging",
+			envSlugs: []string{"staging", "prod"},
+		},
+		{
+			name:        "multiple environments with --no-wait",
+			cfg:         config{envSlugs: []string{"staging", "prod"}, noWait: true},
+			expectedErr: true,
+		},
+		{
+			name:        "multiple environments with --report",
+			cfg:         config{envSlugs: []string{"staging", "prod"}, reports: []string{"github"}},
+			expectedErr: true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+			cfg := test.cfg
+			err := parseEnvSlugs(&cfg)
+			if test.expectedErr {
+				require.Error(err)
+				return
+			}
+			require.NoError(err)
+			require.Equal(test.envSlug, cfg.envSlug)
+			require.Equal(test.envSlugs, cfg.envSlugs)
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/policy"
+	"github.com/airplanedev/cli/pkg/print"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/airplanedev/lib/pkg/deploy/discover"
+	"github.com/airplanedev/lib/pkg/deploy/taskdir/definitions"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// planChangesExitCode is the exit code of `deploy --plan` when the plan contains changes.
+const planChangesExitCode = 2
+
+// Plan describes what a deploy would do, without deploying anything.
+type Plan struct {
+	EnvSlug    string     `json:"envSlug" yaml:"envSlug"`
+	HasChanges bool       `json:"hasChanges" yaml:"hasChanges"`
+	Tasks      []PlanItem `json:"tasks" yaml:"tasks"`
+	Views      []PlanItem `json:"views" yaml:"views"`
+}
+
+type PlanStatus string
+
+const (
+	PlanStatusNew       PlanStatus = "new"
+	PlanStatusChanged   PlanStatus = "changed"
+	PlanStatusUnchanged PlanStatus = "unchanged"
+)
+
+// PlanItem is a single task or view in a plan.
+type PlanItem struct {
+	Slug           string              `json:"slug" yaml:"slug"`
+	Kind           string              `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Status         PlanStatus          `json:"status" yaml:"status"`
+	DefinitionFile string              `json:"definitionFile,omitempty" yaml:"definitionFile,omitempty"`
+	BuildRoot      string              `json:"buildRoot,omitempty" yaml:"buildRoot,omitempty"`
+	Changes        []utils.FieldChange `json:"changes,omitempty" yaml:"changes,omitempty"`
+	Resources      []string            `json:"resources,omitempty" yaml:"resources,omitempty"`
+	Configs        []string            `json:"configs,omitempty" yaml:"configs,omitempty"`
+}
+
+// printPlan prints the plan for deploying the given configs to each environment. If any plan has
+// changes, an error is returned so that the CLI exits with planChangesExitCode.
+func (d *deployer) printPlan(ctx context.Context, taskConfigs []discover.TaskConfig, viewConfigs []discover.ViewConfig, createdTasks, createdViews map[string]bool) error {
+	var plans []Plan
+	for _, envSlug := range d.envSlugs() {
+		plan, err := d.forEnv(envSlug).getPlan(ctx, taskConfigs, viewConfigs, createdTasks, createdViews)
+		if err != nil {
+			return err
+		}
+		plans = append(plans, plan)
+	}
+
+	// Plans for a single environment are printed on their own, to keep the output stable.
+	var out interface{} = plans
+	if len(plans) == 1 {
+		out = plans[0]
+	}
+	print.Print(out, func() {
+		print.YAML{}.Encode(out)
+	})
+
+	var hasChanges bool
+	for _, plan := range plans {
+		counts := map[PlanStatus]int{}
+		for _, item := range append(append([]PlanItem{}, plan.Tasks...), plan.Views...) {
+			counts[item.Status]++
+		}
+		summary := fmt.Sprintf("%d new, %d changed, %d unchanged.", counts[PlanStatusNew], counts[PlanStatusChanged], counts[PlanStatusUnchanged])
+		if len(plans) == 1 {
+			d.logger.Log("Plan: %s", summary)
+		} else {
+			d.logger.Log("Plan for %s: %s", EnvName(plan.EnvSlug), summary)
+		}
+		hasChanges = hasChanges || plan.HasChanges
+	}
+
+	if hasChanges {
+		return utils.ExitCodeError{Code: planChangesExitCode}
+	}
+	return nil
+}
+
+func (d *deployer) getPlan(ctx context.Context, taskConfigs []discover.TaskConfig, viewConfigs []discover.ViewConfig, createdTasks, createdViews map[string]bool) (Plan, error) {
+	plan := Plan{
+		EnvSlug: d.cfg.envSlug,
+		Tasks:   []PlanItem{},
+		Views:   []PlanItem{},
+	}
+	for _, tc := range taskConfigs {
+		item, err := d.getTaskPlan(ctx, tc, createdTasks[tc.TaskID])
+		if err != nil {
+			return Plan{}, err
+		}
+		plan.Tasks = append(plan.Tasks, item)
+		plan.HasChanges = plan.HasChanges || item.Status != PlanStatusUnchanged
+	}
+	for _, vc := range viewConfigs {
+		item, err := d.getViewPlan(ctx, vc, createdViews[vc.ID])
+		if err != nil {
+			return Plan{}, err
+		}
+		plan.Views = append(plan.Views, item)
+		plan.HasChanges = plan.HasChanges || item.Status != PlanStatusUnchanged
+	}
+	return plan, nil
+}
+
+func (d *deployer) getTaskPlan(ctx context.Context, tc discover.TaskConfig, isNew bool) (PlanItem, error) {
+	slug := tc.Def.GetSlug()
+	kind, _, err := tc.Def.GetKindAndOptions()
+	if err != nil {
+		return PlanItem{}, err
+	}
+	item := PlanItem{
+		Slug: slug,
+		Kind: string(kind),
+	}
+	if tc.Source == discover.ConfigSourceDefn {
+		item.DefinitionFile = RelPath(tc.Def.GetDefnFilePath())
+	}
+	if _, err := tc.Def.Entrypoint(); err == nil {
+		item.BuildRoot = RelPath(tc.TaskRoot)
+	} else if err != definitions.ErrNoEntrypoint {
+		return PlanItem{}, err
+	}
+
+	item.Configs, err = getConfigDependencies(tc.Def)
+	if err != nil {
+		return PlanItem{}, err
+	}
+
+	item.Resources = policy.ResourceDependencies(tc.Def)
+
+	// Tasks that are about to be created do not have an ID yet.
+	if isNew || tc.TaskID == "" {
+		item.Status = PlanStatusNew
+		return item, nil
+	}
+	diff, err := DiffTask(ctx, d.cfg.client, d.cfg.envSlug, tc.Def)
+	if err != nil {
+		return PlanItem{}, err
+	}
+	if diff.OldYAML == nil {
+		item.Status = PlanStatusNew
+		return item, nil
+	}
+
+	item.Changes = diff.Changes
+	item.Status = PlanStatusUnchanged
+	if len(item.Changes) > 0 {
+		item.Status = PlanStatusChanged
+	}
+	return item, nil
+}
+
+// TaskDefinitionDiff is the difference between a local task definition and the deployed task.
+type TaskDefinitionDiff struct {
+	// OldYAML is the deployed definition, or nil if the task has not been deployed.
+	OldYAML []byte
+	NewYAML []byte
+	Changes []utils.FieldChange
+}
+
+// DiffTask compares a local task definition with the task that is deployed to envSlug.
+func DiffTask(ctx context.Context, client api.APIClient, envSlug string, def definitions.DefinitionInterface) (TaskDefinitionDiff, error) {
+	newYAML, err := def.Marshal(definitions.DefFormatYAML)
+	if err != nil {
+		return TaskDefinitionDiff{}, errors.Wrap(err, "Error marshalling new task definition")
+	}
+	oldYAML, err := getDeployedDefinition(ctx, client, envSlug, def.GetSlug())
+	if err != nil {
+		return TaskDefinitionDiff{}, err
+	}
+	diff := TaskDefinitionDiff{OldYAML: oldYAML, NewYAML: newYAML}
+	if oldYAML == nil {
+		return diff, nil
+	}
+
+	var oldDef, newDef interface{}
+	if err := yaml.Unmarshal(oldYAML, &oldDef); err != nil {
+		return TaskDefinitionDiff{}, errors.Wrap(err, "Error parsing current task definition")
+	}
+	if err := yaml.Unmarshal(newYAML, &newDef); err != nil {
+		return TaskDefinitionDiff{}, errors.Wrap(err, "Error parsing new task definition")
+	}
+	diff.Changes = utils.DiffFields("", oldDef, newDef)
+	return diff, nil
+}
+
+func (d *deployer) getViewPlan(ctx context.Context, vc discover.ViewConfig, isNew bool) (PlanItem, error) {
+	item := PlanItem{
+		Slug:      vc.Def.Slug,
+		BuildRoot: RelPath(vc.Root),
+	}
+	for _, v := range vc.Def.EnvVars {
+		if v.Config != nil {
+			item.Configs = append(item.Configs, *v.Config)
+		}
+	}
+	sort.Strings(item.Configs)
+
+	// Views that are about to be created do not have an ID yet.
+	if isNew || vc.ID == "" {
+		item.Status = PlanStatusNew
+		return item, nil
+	}
+	oldYAML, err := d.getCurrentViewDefinition(ctx, vc.Def.Slug)
+	if err != nil {
+		return PlanItem{}, err
+	}
+	if oldYAML == nil {
+		item.Status = PlanStatusNew
+		return item, nil
+	}
+	newYAML, err := marshalViewDefinition(vc.Def)
+	if err != nil {
+		return PlanItem{}, err
+	}
+
+	var oldDef, newDef interface{}
+	if err := yaml.Unmarshal(oldYAML, &oldDef); err != nil {
+		return PlanItem{}, errors.Wrap(err, "Error parsing current view definition")
+	}
+	if err := yaml.Unmarshal(newYAML, &newDef); err != nil {
+		return PlanItem{}, errors.Wrap(err, "Error parsing new view definition")
+	}
+	item.Changes = utils.DiffFields("", oldDef, newDef)
+	item.Status = PlanStatusUnchanged
+	if len(item.Changes) > 0 {
+		item.Status = PlanStatusChanged
+	}
+	return item, nil
+}
+
+// getConfigDependencies returns the configs that a task reads, either through its environment
+// variables or as config attachments.
+func getConfigDependencies(def definitions.DefinitionInterface) ([]string, error) {
+	seen := map[string]bool{}
+	env, err := def.GetEnv()
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range env {
+		if v.Config != nil {
+			seen[*v.Config] = true
+		}
+	}
+	configAttachments, err := def.GetConfigAttachments()
+	if err != nil {
+		return nil, err
+	}
+	for _, ca := range configAttachments {
+		seen[ca.NameTag] = true
+	}
+	return sortedKeys(seen), nil
+}
+
+func sortedKeys(m map[string]bool) []string {
+	if len(m) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,47 @@
+package deploy
+
+import (
+	"os"
+
+	"github.com/airplanedev/cli/pkg/ci"
+	"github.com/airplanedev/cli/pkg/policy"
+	"github.com/airplanedev/lib/pkg/deploy/discover"
+	"github.com/pkg/errors"
+)
+
+// checkPolicies checks task definitions against the policy config of the working directory.
+// Findings with error severity block the deploy.
+func (d *deployer) checkPolicies(taskConfigs []discover.TaskConfig) error {
+	wd, err := os.Getwd()
+	if err != nil {
+		return errors.Wrap(err, "getting working directory")
+	}
+	cfg, err := policy.FindConfig(wd)
+	if err != nil {
+		return err
+	}
+
+	var findings []policy.Finding
+	for _, tc := range taskConfigs {
+		t, err := policy.NewTask(tc.Def)
+		if err != nil {
+			return err
+		}
+		findings = append(findings, policy.Check(cfg, t)...)
+	}
+	if len(findings) == 0 {
+		return nil
+	}
+
+	policy.Log(d.logger, findings)
+	d.logger.Log("")
+	if ci.InGitHubActions() {
+		if err := ci.WriteGitHubAnnotations(os.Stdout, policy.Annotations(findings)); err != nil {
+			return err
+		}
+	}
+	if policy.HasErrors(findings) {
+		return errors.New("task definitions violate policies with error severity")
+	}
+	return nil
+}
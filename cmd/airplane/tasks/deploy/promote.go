@@ -0,0 +1,413 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/airplanedev/cli/pkg/utils"
+	libapi "github.com/airplanedev/lib/pkg/api"
+	libBuild "github.com/airplanedev/lib/pkg/build"
+	"github.com/airplanedev/lib/pkg/deploy/taskdir/definitions"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+type promoteConfig struct {
+	root   *cli.Config
+	client api.APIClient
+	slugs  []string
+	views  []string
+
+	fromEnvSlug string
+	toEnvSlug   string
+
+	assumeYes bool
+	assumeNo  bool
+	noWait    bool
+}
+
+func newPromoteCmd(c *cli.Config) *cobra.Command {
+	var cfg = promoteConfig{
+		root:   c,
+		client: c.Client,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "promote [slugs...]",
+		Short: "Promote tasks and views from one environment to another",
+		Long: heredoc.Doc(`
+			Deploy tasks from one environment to another without rebuilding them. Each task is
+			deployed to the target environment with the definition and image that are currently
+			deployed in the source environment.
+
+			Views given with --view are deployed to the target environment from the same source
+			as their last successful deployment to the source environment. Views are built
+			separately for each environment, so they are rebuilt in the target environment.
+
+			If no slugs or views are given, every task in the source environment is promoted.
+		`),
+		Example: heredoc.Doc(`
+			airplane deploy promote --from staging --to prod
+			airplane deploy promote --from staging --to prod my_task my_other_task
+			airplane deploy promote --from staging --to prod my_task --view my_view
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.slugs = args
+			return runPromote(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.fromEnvSlug, "from", "", "The slug of the environment to promote tasks from. Defaults to your team's default environment.")
+	cmd.Flags().StringVar(&cfg.toEnvSlug, "to", "", "The slug of the environment to promote tasks to. Defaults to your team's default environment.")
+	cmd.Flags().StringArrayVar(&cfg.views, "view", nil, "The slug of a view to promote. Can be repeated.")
+	cmd.Flags().BoolVar(&cfg.noWait, "no-wait", false, "Print the ID of the deployment and exit without waiting for it to finish.")
+	cmd.Flags().BoolVarP(&cfg.assumeYes, "yes", "y", false, "True to specify automatic yes to prompts.")
+	cmd.Flags().BoolVarP(&cfg.assumeNo, "no", "n", false, "True to specify automatic no to prompts.")
+
+	if err := cmd.Flags().MarkHidden("yes"); err != nil {
+		logger.Debug("error: %s", err)
+	}
+	if err := cmd.Flags().MarkHidden("no"); err != nil {
+		logger.Debug("error: %s", err)
+	}
+
+	return cmd
+}
+
+// promotion is a task that is being promoted to the target environment.
+type promotion struct {
+	source libapi.Task
+	def    definitions.Definition_0_3
+	// target is the task in the target environment, or nil if the task does not exist there
+	// yet.
+	target *libapi.Task
+}
+
+// viewPromotion is a view that is being promoted to the target environment.
+type viewPromotion struct {
+	// source is the view as it was last deployed to the source environment.
+	source api.DeployView
+	// current is the view as it is currently deployed.
+	current libapi.View
+}
+
+func runPromote(ctx context.Context, cfg promoteConfig) error {
+	if cfg.assumeYes && cfg.assumeNo {
+		return errors.New("Cannot specify both --yes and --no")
+	}
+	if cfg.fromEnvSlug == cfg.toEnvSlug {
+		return errors.New("--from and --to must be different environments")
+	}
+
+	l := logger.NewStdErrLogger(logger.StdErrLoggerOpts{WithLoader: true})
+	defer l.StopLoader()
+
+	// The deployer deploys to the target environment.
+	d := NewDeployer(config{
+		root:           cfg.root,
+		client:         cfg.client,
+		assumeYes:      cfg.assumeYes,
+		assumeNo:       cfg.assumeNo,
+		noWait:         cfg.noWait,
+		noArchiveCache: true,
+		envSlug:        cfg.toEnvSlug,
+	}, l, DeployerOpts{})
+
+	slugs := cfg.slugs
+	if len(slugs) == 0 && len(cfg.views) == 0 {
+		resp, err := cfg.client.ListTasks(ctx, cfg.fromEnvSlug)
+		if err != nil {
+			return errors.Wrap(err, "listing tasks")
+		}
+		for _, t := range resp.Tasks {
+			slugs = append(slugs, t.Slug)
+		}
+		sort.Strings(slugs)
+	}
+	if len(slugs) == 0 && len(cfg.views) == 0 {
+		l.Log("Nothing to promote")
+		return nil
+	}
+
+	var promotions []promotion
+	for _, slug := range slugs {
+		p, err := d.getPromotion(ctx, slug, cfg.fromEnvSlug)
+		if err != nil {
+			return err
+		}
+		promotions = append(promotions, p)
+	}
+	var viewPromotions []viewPromotion
+	for _, slug := range cfg.views {
+		vp, err := getViewPromotion(ctx, cfg.client, slug, cfg.fromEnvSlug)
+		if err != nil {
+			return err
+		}
+		viewPromotions = append(viewPromotions, vp)
+	}
+
+	if len(promotions) > 0 {
+		l.Log("Promoting %d %s from %s to %s:", len(promotions), pluralize(len(promotions), "task", "tasks"), EnvName(cfg.fromEnvSlug), EnvName(cfg.toEnvSlug))
+		for _, p := range promotions {
+			l.Log("- %s", logger.Bold(p.source.Slug))
+			diff, err := getPromotionDiff(ctx, cfg.client, p)
+			if err != nil {
+				return err
+			}
+			for _, line := range diff {
+				l.Log("  %s", line)
+			}
+		}
+		l.Log("")
+	}
+	if len(viewPromotions) > 0 {
+		l.Log("Promoting %d %s from %s to %s:", len(viewPromotions), pluralize(len(viewPromotions), "view", "views"), EnvName(cfg.fromEnvSlug), EnvName(cfg.toEnvSlug))
+		for _, vp := range viewPromotions {
+			l.Log("- %s", logger.Bold(vp.current.Slug))
+			diff, err := getViewPromotionDiff(vp)
+			if err != nil {
+				return err
+			}
+			for _, line := range diff {
+				l.Log("  %s", line)
+			}
+		}
+		l.Log("")
+	}
+
+	if err := d.confirmDeployment(ctx); err != nil {
+		if err == skippedDeployErr {
+			return nil
+		}
+		return err
+	}
+
+	tasksToDeploy := make([]api.DeployTask, 0, len(promotions))
+	for _, p := range promotions {
+		taskToDeploy, err := d.getPromoteTask(ctx, p)
+		if err != nil {
+			return err
+		}
+		tasksToDeploy = append(tasksToDeploy, taskToDeploy)
+	}
+
+	viewsToDeploy := make([]api.DeployView, 0, len(viewPromotions))
+	for _, vp := range viewPromotions {
+		viewsToDeploy = append(viewsToDeploy, vp.source)
+	}
+
+	resp, err := cfg.client.CreateDeployment(ctx, api.CreateDeploymentRequest{
+		Tasks:   tasksToDeploy,
+		Views:   viewsToDeploy,
+		EnvSlug: cfg.toEnvSlug,
+	})
+	if err != nil {
+		return err
+	}
+	l.Log(logger.Purple(fmt.Sprintf("\nView deployment: %s\n", cfg.client.DeploymentURL(resp.Deployment.ID, cfg.toEnvSlug))))
+
+	if cfg.noWait {
+		fmt.Println(resp.Deployment.ID)
+		return nil
+	}
+	return d.waitForDeploy(ctx, cfg.client, resp.Deployment.ID)
+}
+
+// getPromotion looks up a task in the source and target environments.
+func (d *deployer) getPromotion(ctx context.Context, slug, fromEnvSlug string) (promotion, error) {
+	source, err := d.cfg.client.GetTask(ctx, libapi.GetTaskRequest{
+		Slug:    slug,
+		EnvSlug: fromEnvSlug,
+	})
+	if err != nil {
+		return promotion{}, errors.Wrapf(err, "getting task %s from %s", slug, EnvName(fromEnvSlug))
+	}
+	if ok, err := libBuild.NeedsBuilding(source.Kind); err != nil {
+		return promotion{}, err
+	} else if ok && source.Image == nil {
+		return promotion{}, errors.Errorf("task %s has not been built in %s", slug, EnvName(fromEnvSlug))
+	}
+
+	def, err := definitions.NewDefinitionFromTask_0_3(ctx, d.cfg.client, source)
+	if err != nil {
+		return promotion{}, err
+	}
+
+	p := promotion{
+		source: source,
+		def:    def,
+	}
+	target, err := d.cfg.client.GetTask(ctx, libapi.GetTaskRequest{
+		Slug:    slug,
+		EnvSlug: d.cfg.envSlug,
+	})
+	if err != nil {
+		if _, ok := err.(*libapi.TaskMissingError); !ok {
+			return promotion{}, err
+		}
+	} else {
+		p.target = &target
+	}
+	return p, nil
+}
+
+// getViewPromotion looks up the view and the source it was last successfully deployed from to
+// the source environment.
+func getViewPromotion(ctx context.Context, client api.APIClient, slug, fromEnvSlug string) (viewPromotion, error) {
+	current, err := client.GetView(ctx, libapi.GetViewRequest{Slug: slug})
+	if err != nil {
+		return viewPromotion{}, errors.Wrapf(err, "getting view %s", slug)
+	}
+
+	resp, err := client.ListDeployments(ctx, api.ListDeploymentsRequest{
+		EnvSlug:  fromEnvSlug,
+		ViewSlug: slug,
+	})
+	if err != nil {
+		return viewPromotion{}, errors.Wrap(err, "listing deployments")
+	}
+	for _, dep := range resp.Deployments {
+		if dep.SucceededAt == nil {
+			continue
+		}
+		dep, err := client.GetDeployment(ctx, dep.ID)
+		if err != nil {
+			return viewPromotion{}, errors.Wrapf(err, "getting deployment %s", dep.ID)
+		}
+		for _, v := range dep.Views {
+			if v.UpdateViewRequest.Slug == slug {
+				v.ID = current.ID
+				return viewPromotion{source: v, current: current}, nil
+			}
+		}
+		return viewPromotion{}, errors.Errorf("view %s is missing from deployment %s", slug, dep.ID)
+	}
+	return viewPromotion{}, errors.Errorf("view %s has not been deployed to %s", slug, EnvName(fromEnvSlug))
+}
+
+// getViewPromotionDiff returns the changes that promoting a view makes to its definition.
+func getViewPromotionDiff(vp viewPromotion) ([]string, error) {
+	oldYAML, err := yaml.Marshal(viewDefinitionFields{
+		Slug:        vp.current.Slug,
+		Name:        vp.current.Name,
+		Description: vp.current.Description,
+		EnvVars:     vp.current.EnvVars,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error marshalling current view definition")
+	}
+	newYAML, err := yaml.Marshal(viewDefinitionFields{
+		Slug:        vp.source.UpdateViewRequest.Slug,
+		Name:        vp.source.UpdateViewRequest.Name,
+		Description: vp.source.UpdateViewRequest.Description,
+		EnvVars:     vp.source.UpdateViewRequest.EnvVars,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Error marshalling promoted view definition")
+	}
+
+	label := vp.current.Slug
+	difflines := utils.UnifiedDiff("a/"+label, "b/"+label, string(oldYAML), string(newYAML))
+	if difflines == nil {
+		return []string{"(no changes to view definition)"}, nil
+	}
+	return difflines, nil
+}
+
+// getPromotionDiff returns the changes that promoting a task makes to its definition in the
+// target environment.
+func getPromotionDiff(ctx context.Context, client api.APIClient, p promotion) ([]string, error) {
+	if p.target == nil {
+		return []string{"(task created in new environment)"}, nil
+	}
+	oldYAML, err := marshalTaskDefinition(ctx, client, *p.target)
+	if err != nil {
+		return nil, err
+	}
+	newYAML, err := p.def.Marshal(definitions.DefFormatYAML)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error marshalling promoted task definition")
+	}
+
+	label := p.source.Slug + ".task.yaml"
+	difflines := utils.UnifiedDiff("a/"+label, "b/"+label, string(oldYAML), string(newYAML))
+	if difflines == nil {
+		return []string{"(no changes to task definition)"}, nil
+	}
+	return difflines, nil
+}
+
+// getPromoteTask returns the request to deploy a promoted task to the target environment,
+// creating the task there if necessary. The task is deployed with the image that was built
+// for the source environment.
+func (d *deployer) getPromoteTask(ctx context.Context, p promotion) (api.DeployTask, error) {
+	if err := ensureConfigVarsExist(ctx, d.cfg.client, d.logger, &p.def, d.cfg.envSlug); err != nil {
+		return api.DeployTask{}, err
+	}
+
+	var taskID string
+	if p.target != nil {
+		taskID = p.target.ID
+	} else {
+		// Don't prompt again: the user has already confirmed the promotion.
+		cfg := d.cfg
+		cfg.assumeYes = true
+		metadata, err := HandleMissingTask(cfg, d.logger, nil)(ctx, &p.def)
+		if err != nil {
+			return api.DeployTask{}, err
+		}
+		if metadata == nil {
+			return api.DeployTask{}, errors.Errorf("unable to create task %s", p.source.Slug)
+		}
+		taskID = metadata.ID
+	}
+
+	return NewRedeployTask(ctx, d.cfg.client, taskID, p.source, p.def, d.cfg.envSlug)
+}
+
+// NewRedeployTask returns the request to deploy the task with the given ID with def, the
+// definition of a task that has already been deployed, and the image that it was built into.
+// The task is not rebuilt.
+func NewRedeployTask(ctx context.Context, client api.APIClient, taskID string, deployed libapi.Task, def definitions.Definition_0_3, envSlug string) (api.DeployTask, error) {
+	utr, err := def.GetUpdateTaskRequest(ctx, client)
+	if err != nil {
+		return api.DeployTask{}, err
+	}
+	utr.Image = deployed.Image
+	utr.InterpolationMode = &deployed.InterpolationMode
+	utr.EnvSlug = envSlug
+
+	env, err := def.GetEnv()
+	if err != nil {
+		return api.DeployTask{}, err
+	}
+
+	return api.DeployTask{
+		TaskID:            taskID,
+		Kind:              deployed.Kind,
+		UpdateTaskRequest: utr,
+		EnvVars:           env,
+		Schedules:         def.GetSchedules(),
+	}, nil
+}
+
+// EnvName returns the name of an environment for messages.
+func EnvName(envSlug string) string {
+	if envSlug == "" {
+		return "the default environment"
+	}
+	return envSlug
+}
+
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
@@ -0,0 +1,50 @@
+package deploy
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/airplanedev/cli/pkg/api"
+	libapi "github.com/airplanedev/lib/pkg/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetViewPromotion(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	now := time.Now()
+	client := &api.MockClient{
+		Views: map[string]libapi.View{
+			"my_view": {ID: "vew123", Slug: "my_view", Name: "My view"},
+		},
+		Deployments: []api.Deployment{
+			// Deployments are listed newest first.
+			{ID: "dep3", FailedAt: &now},
+			{ID: "dep2", SucceededAt: &now, Views: []api.DeployView{
+				{UploadID: "upl_other", UpdateViewRequest: libapi.UpdateViewRequest{Slug: "other_view"}},
+				{UploadID: "upl2", UpdateViewRequest: libapi.UpdateViewRequest{Slug: "my_view", Name: "My renamed view"}},
+			}},
+			{ID: "dep1", SucceededAt: &now, Views: []api.DeployView{
+				{UploadID: "upl1", UpdateViewRequest: libapi.UpdateViewRequest{Slug: "my_view", Name: "My view"}},
+			}},
+		},
+	}
+
+	vp, err := getViewPromotion(ctx, client, "my_view", "staging")
+	require.NoError(err)
+	require.Equal("vew123", vp.source.ID)
+	require.Equal("upl2", vp.source.UploadID)
+
+	diff, err := getViewPromotionDiff(vp)
+	require.NoError(err)
+	out := stripANSI(strings.Join(diff, "\n"))
+	require.Contains(out, "-name: My view\n")
+	require.Contains(out, "+name: My renamed view\n")
+
+	client.Deployments = []api.Deployment{{ID: "dep3", FailedAt: &now}}
+	_, err = getViewPromotion(ctx, client, "my_view", "staging")
+	require.EqualError(err, "view my_view has not been deployed to staging")
+}
@@ -0,0 +1,281 @@
+package deploy
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/ci"
+	"github.com/pkg/errors"
+)
+
+// maxReportLogLines is the number of log lines of each task or view that reports include.
+const maxReportLogLines = 20
+
+type reportStatus string
+
+const (
+	reportStatusSucceeded reportStatus = "succeeded"
+	reportStatusFailed    reportStatus = "failed"
+	reportStatusSkipped   reportStatus = "skipped"
+)
+
+// deployReport collects the results of a deployment for each task and view, so that they can
+// be reported to CI systems.
+type deployReport struct {
+	deploymentID string
+	start        time.Time
+	end          time.Time
+	items        []*deployReportItem
+	// byKey maps the kind and slug of each item to it, since a task and a view can share a slug.
+	byKey map[reportKey]*deployReportItem
+}
+
+type reportKey struct {
+	kind string
+	slug string
+}
+
+type deployReportItem struct {
+	// kind is either "tasks" or "views".
+	kind           string
+	slug           string
+	definitionFile string
+
+	firstLog time.Time
+	lastLog  time.Time
+	logs     []string
+
+	status        reportStatus
+	failureReason string
+}
+
+func newDeployReport() *deployReport {
+	return &deployReport{
+		start: time.Now(),
+		byKey: map[reportKey]*deployReportItem{},
+	}
+}
+
+func (r *deployReport) add(kind, slug, definitionFile string) {
+	item := &deployReportItem{
+		kind:           kind,
+		slug:           slug,
+		definitionFile: definitionFile,
+	}
+	r.items = append(r.items, item)
+	r.byKey[reportKey{kind, slug}] = item
+}
+
+// addLog adds a build log to the task or view that it belongs to. Logs only have the slug of the
+// task or view, so the logs of a view that shares its slug with a task are added to the task.
+func (r *deployReport) addLog(l api.LogItem) {
+	item, ok := r.byKey[reportKey{"tasks", l.TaskSlug}]
+	if !ok {
+		item, ok = r.byKey[reportKey{"views", l.TaskSlug}]
+	}
+	if !ok {
+		return
+	}
+	if item.firstLog.IsZero() {
+		item.firstLog = l.Timestamp
+	}
+	item.lastLog = l.Timestamp
+	item.logs = append(item.logs, strings.TrimPrefix(l.Text, "[builder] "))
+	if len(item.logs) > maxReportLogLines {
+		item.logs = item.logs[len(item.logs)-maxReportLogLines:]
+	}
+}
+
+// finish records the outcome of the deployment for every task and view. A failed deployment
+// only reports the tasks and views that its failure reason mentions as failed; if it mentions
+// none, all of them are reported as failed.
+func (r *deployReport) finish(deployment api.Deployment) {
+	r.end = time.Now()
+	switch {
+	case deployment.SucceededAt != nil:
+		for _, item := range r.items {
+			item.status = reportStatusSucceeded
+		}
+	case deployment.FailedAt != nil:
+		var blamed []*deployReportItem
+		for _, item := range r.items {
+			if mentionsSlug(deployment.FailedReason, item.slug) {
+				blamed = append(blamed, item)
+			}
+		}
+		if len(blamed) == 0 {
+			blamed = r.items
+		}
+		for _, item := range r.items {
+			item.status = reportStatusSkipped
+			item.failureReason = "Not deployed because another task or view failed to deploy"
+		}
+		for _, item := range blamed {
+			item.status = reportStatusFailed
+			item.failureReason = deployment.FailedReason
+		}
+	case deployment.CancelledAt != nil:
+		for _, item := range r.items {
+			item.status = reportStatusSkipped
+			item.failureReason = "Deployment cancelled"
+		}
+	}
+}
+
+// abort records the outcome of a deploy that ended before its deployment finished, e.g.
+// because a check failed or the build roots could not be uploaded. If err is nil, the deploy was
+// skipped.
+func (r *deployReport) abort(err error) {
+	status, reason := reportStatusSkipped, "Not deployed"
+	if err != nil {
+		status, reason = reportStatusFailed, err.Error()
+	}
+	for _, item := range r.items {
+		if item.status == "" {
+			item.status = status
+			item.failureReason = reason
+		}
+	}
+	if r.end.IsZero() {
+		r.end = time.Now()
+	}
+}
+
+// mentionsSlug returns true if text contains slug as a whole word, so that e.g. my_task is not
+// blamed for a failure of my_task_v2.
+func mentionsSlug(text, slug string) bool {
+	isSlugChar := func(b byte) bool {
+		return b == '_' || b == '-' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+	}
+	for i := 0; i+len(slug) <= len(text); {
+		j := strings.Index(text[i:], slug)
+		if j < 0 {
+			return false
+		}
+		start, end := i+j, i+j+len(slug)
+		if (start == 0 || !isSlugChar(text[start-1])) && (end == len(text) || !isSlugChar(text[end])) {
+			return true
+		}
+		i = start + 1
+	}
+	return false
+}
+
+func (item *deployReportItem) duration() time.Duration {
+	return item.lastLog.Sub(item.firstLog)
+}
+
+// statusAndReason returns the status of the item, treating items of deployments that did
+// not finish as failed.
+func (item *deployReportItem) statusAndReason() (reportStatus, string) {
+	if item.status == "" {
+		return reportStatusFailed, "Deployment did not finish"
+	}
+	return item.status, item.failureReason
+}
+
+func (r *deployReport) junit() ci.JUnitTestSuites {
+	end := r.end
+	if end.IsZero() {
+		end = time.Now()
+	}
+	name := "deployment"
+	if r.deploymentID != "" {
+		name += " " + r.deploymentID
+	}
+	suite := ci.JUnitTestSuite{
+		Name:      name,
+		Time:      end.Sub(r.start).Seconds(),
+		Timestamp: r.start.UTC().Format(time.RFC3339),
+	}
+	for _, item := range r.items {
+		tc := ci.JUnitTestCase{
+			ClassName: item.kind,
+			Name:      item.slug,
+			File:      ci.RelativePath(item.definitionFile),
+			Time:      item.duration().Seconds(),
+			SystemOut: strings.Join(item.logs, "\n"),
+		}
+		if item.definitionFile == "" {
+			tc.File = ""
+		}
+		switch status, reason := item.statusAndReason(); status {
+		case reportStatusFailed:
+			tc.Failure = &ci.JUnitFailure{Message: reason, Text: strings.Join(item.logs, "\n")}
+		case reportStatusSkipped:
+			tc.Skipped = &ci.JUnitSkipped{Message: reason}
+		}
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+	return ci.JUnitTestSuites{
+		Name:   "airplane deploy",
+		Time:   suite.Time,
+		Suites: []ci.JUnitTestSuite{suite},
+	}
+}
+
+// annotations returns an annotation for each task or view that failed to deploy.
+func (r *deployReport) annotations() []ci.Annotation {
+	var annotations []ci.Annotation
+	for _, item := range r.items {
+		status, reason := item.statusAndReason()
+		if status != reportStatusFailed {
+			continue
+		}
+		message := reason
+		if len(item.logs) > 0 {
+			message += "\n\n" + strings.Join(item.logs, "\n")
+		}
+		annotations = append(annotations, ci.Annotation{
+			Level:   ci.AnnotationError,
+			Title:   fmt.Sprintf("Failed to deploy %s", item.slug),
+			File:    item.definitionFile,
+			Message: message,
+		})
+	}
+	return annotations
+}
+
+// writeReports writes the reports that were requested with --report.
+func (d *deployer) writeReports() error {
+	if d.report == nil {
+		return nil
+	}
+	if d.cfg.junitReportPath != "" {
+		if err := ci.WriteJUnitFile(d.cfg.junitReportPath, d.report.junit()); err != nil {
+			return err
+		}
+		d.logger.Debug("Wrote JUnit report to %s", d.cfg.junitReportPath)
+	}
+	if d.cfg.githubReport {
+		if err := ci.WriteGitHubAnnotations(os.Stdout, d.report.annotations()); err != nil {
+			return errors.Wrap(err, "writing GitHub annotations")
+		}
+	}
+	return nil
+}
+
+// parseReports parses the values of --report, e.g. `junit=report.xml` or `github`.
+func parseReports(cfg *config) error {
+	for _, report := range cfg.reports {
+		kind, path, _ := strings.Cut(report, "=")
+		switch kind {
+		case "junit":
+			if path == "" {
+				return errors.New("--report junit requires a path, e.g. --report junit=report.xml")
+			}
+			cfg.junitReportPath = path
+		case "github":
+			cfg.githubReport = true
+		default:
+			return errors.Errorf("unknown report %q: expected junit=<path> or github", report)
+		}
+	}
+	if len(cfg.reports) > 0 && cfg.noWait {
+		return errors.New("--report cannot be used with --no-wait")
+	}
+	return nil
+}
@@ -0,0 +1,282 @@
+package deploy
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/pkg/errors"
+)
+
+// secretsAllowlistFile lists, per build root, findings of the secret scanner that are false
+// positives. Each line is either a path, which may contain wildcards, or a path and a line
+// number separated by a colon.
+const secretsAllowlistFile = ".airplanesecrets"
+
+// maxScannedFileSize is the size above which files are not scanned for secrets.
+const maxScannedFileSize = 1 << 20
+
+// unscannedDirs are the directories, at any depth, whose files are not scanned for secrets:
+// they hold third-party dependencies and local state, which are full of test fixtures and
+// example credentials. Their files are still included in the build archive.
+var unscannedDirs = map[string]bool{
+	".airplane":      true,
+	".airplane-view": true,
+	".venv":          true,
+	"venv":           true,
+	"__pycache__":    true,
+	"node_modules":   true,
+	"vendor":         true,
+}
+
+type secretRule struct {
+	name string
+	re   *regexp.Regexp
+	// minEntropy, if set, is the minimum Shannon entropy of the first submatch of re.
+	minEntropy float64
+}
+
+var secretRules = []secretRule{
+	{name: "private key", re: regexp.MustCompile(`-----BEGIN ((RSA|DSA|EC|OPENSSH|PGP|ENCRYPTED) )?PRIVATE KEY( BLOCK)?-----`)},
+	{name: "AWS access key ID", re: regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{name: "AWS secret access key", re: regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?[A-Za-z0-9/+=]{40}\b`)},
+	{name: "Google Cloud service account key", re: regexp.MustCompile(`"private_key_id"\s*:\s*"[0-9a-f]{40}"`)},
+	{name: "GitHub token", re: regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{82})\b`)},
+	{name: "Slack token", re: regexp.MustCompile(`\bxox[abposr]-[0-9A-Za-z-]{10,}\b`)},
+	{name: "Stripe secret key", re: regexp.MustCompile(`\b[rs]k_live_[0-9A-Za-z]{24,}\b`)},
+	{
+		name:       "high-entropy token",
+		re:         regexp.MustCompile(`(?i)(?:secret|token|passw(?:or)?d|api_?key|access_?key|private_?key)[A-Za-z0-9_]*["']?\s*[:=]\s*["']([A-Za-z0-9+/_\-=.]{20,})["']`),
+		minEntropy: 3.5,
+	},
+}
+
+// secretFinding is a possible secret in a file that is headed into a build archive.
+type secretFinding struct {
+	// Path is relative to the build root, separated by slashes.
+	Path string
+	// Line is 1-indexed.
+	Line int
+	Rule string
+}
+
+// scanSecrets returns the possible secrets in the files that are included in the build archive
+// of root, except for the ones in its allowlist.
+func scanSecrets(root string) ([]secretFinding, error) {
+	allowlist, err := readSecretsAllowlist(root)
+	if err != nil {
+		return nil, err
+	}
+	files, err := listArchiveFiles(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []secretFinding
+	for _, f := range files {
+		if f.Size > maxScannedFileSize || isUnscanned(f.Path) || allowlist.allows(f.Path, 0) {
+			continue
+		}
+		buf, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(f.Path)))
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading %s", f.Path)
+		}
+		for _, finding := range scanFile(f.Path, buf) {
+			if !allowlist.allows(finding.Path, finding.Line) {
+				findings = append(findings, finding)
+			}
+		}
+	}
+	return findings, nil
+}
+
+// isUnscanned returns true if p, relative to the build root, is in one of unscannedDirs.
+func isUnscanned(p string) bool {
+	dirs := strings.Split(path.Dir(p), "/")
+	for _, dir := range dirs {
+		if unscannedDirs[dir] {
+			return true
+		}
+	}
+	return false
+}
+
+// scanFile returns the possible secrets in the contents of a file.
+func scanFile(p string, buf []byte) []secretFinding {
+	// Skip binary files.
+	head := buf
+	if len(head) > 8000 {
+		head = head[:8000]
+	}
+	if bytes.IndexByte(head, 0) >= 0 {
+		return nil
+	}
+
+	isEnvFile := isEnvFile(path.Base(p))
+	var findings []secretFinding
+	scanner := bufio.NewScanner(bytes.NewReader(buf))
+	scanner.Buffer(nil, maxScannedFileSize)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if isEnvFile && isEnvAssignment(text) {
+			findings = append(findings, secretFinding{Path: p, Line: line, Rule: ".env file"})
+			continue
+		}
+		for _, rule := range secretRules {
+			if rule.matches(text) {
+				findings = append(findings, secretFinding{Path: p, Line: line, Rule: rule.name})
+				break
+			}
+		}
+	}
+	return findings
+}
+
+func (r secretRule) matches(line string) bool {
+	if r.minEntropy == 0 {
+		return r.re.MatchString(line)
+	}
+	for _, m := range r.re.FindAllStringSubmatch(line, -1) {
+		if shannonEntropy(m[1]) >= r.minEntropy {
+			return true
+		}
+	}
+	return false
+}
+
+// isEnvFile returns true for files such as `.env` and `.env.production`, but not for example
+// files such as `.env.example`.
+func isEnvFile(name string) bool {
+	if name != ".env" && !strings.HasPrefix(name, ".env.") {
+		return false
+	}
+	switch strings.TrimPrefix(name, ".env.") {
+	case "example", "sample", "template", "defaults":
+		return false
+	}
+	return true
+}
+
+// isEnvAssignment returns true if a line of a .env file assigns a non-empty value.
+func isEnvAssignment(line string) bool {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, "#") {
+		return false
+	}
+	i := strings.Index(line, "=")
+	if i <= 0 {
+		return false
+	}
+	value := strings.Trim(strings.TrimSpace(line[i+1:]), `"'`)
+	return value != ""
+}
+
+// shannonEntropy returns the entropy of s in bits per character.
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	counts := map[rune]int{}
+	n := 0
+	for _, r := range s {
+		counts[r]++
+		n++
+	}
+	var entropy float64
+	for _, c := range counts {
+		p := float64(c) / float64(n)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+type secretsAllowlist struct {
+	// patterns are paths, which may contain wildcards, whose findings are all allowed.
+	patterns []string
+	// lines are findings that are allowed, formatted as `path:line`.
+	lines map[string]bool
+}
+
+// readSecretsAllowlist reads the secrets allowlist of root. If there is no allowlist, an empty
+// allowlist is returned.
+func readSecretsAllowlist(root string) (secretsAllowlist, error) {
+	allowlist := secretsAllowlist{lines: map[string]bool{}}
+	buf, err := os.ReadFile(filepath.Join(root, secretsAllowlistFile))
+	if os.IsNotExist(err) {
+		return allowlist, nil
+	} else if err != nil {
+		return allowlist, errors.Wrapf(err, "reading %s", secretsAllowlistFile)
+	}
+
+	for _, line := range strings.Split(string(buf), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "./")
+		if i := strings.LastIndex(line, ":"); i > 0 {
+			if _, err := strconv.Atoi(line[i+1:]); err == nil {
+				allowlist.lines[line] = true
+				continue
+			}
+		}
+		if _, err := path.Match(line, ""); err != nil {
+			return allowlist, errors.Wrapf(err, "invalid pattern %q in %s", line, secretsAllowlistFile)
+		}
+		allowlist.patterns = append(allowlist.patterns, line)
+	}
+	return allowlist, nil
+}
+
+// allows returns true if findings in the file at p are allowed, or if line is nonzero, if the
+// finding at that line is.
+func (a secretsAllowlist) allows(p string, line int) bool {
+	for _, pattern := range a.patterns {
+		if ok, _ := path.Match(pattern, p); ok {
+			return true
+		}
+	}
+	return line > 0 && a.lines[fmt.Sprintf("%s:%d", p, line)]
+}
+
+// checkSecrets scans the build roots for secrets. Findings block the deploy, unless
+// --allow-secrets was passed.
+func (d *deployer) checkSecrets(roots []string) error {
+	var total int
+	for _, root := range roots {
+		findings, err := scanSecrets(root)
+		if err != nil {
+			return err
+		}
+		if len(findings) == 0 {
+			continue
+		}
+		total += len(findings)
+
+		d.logger.Log("Found possible secrets in %s:", logger.Bold(RelPath(root)))
+		for _, f := range findings {
+			d.logger.Log("  %s:%d  %s", f.Path, f.Line, logger.Red("%s", f.Rule))
+		}
+		d.logger.Log("")
+	}
+	if total == 0 {
+		return nil
+	}
+
+	if d.cfg.allowSecrets {
+		d.logger.Warning("Deploying anyway because --allow-secrets was passed.")
+		return nil
+	}
+	d.logger.Log("Remove secrets from your build roots or add them to %s. If a finding is a false positive,", airplaneIgnoreFile)
+	d.logger.Log("add `<path>` or `<path>:<line>` to the %s file in its build root.", secretsAllowlistFile)
+	d.logger.Log("")
+	return errors.Errorf("found %d possible %s", total, pluralize(total, "secret", "secrets"))
+}
@@ -13,11 +13,14 @@ import (
 	"github.com/airplanedev/cli/cmd/airplane/tasks/dev/config"
 	viewsdev "github.com/airplanedev/cli/cmd/airplane/views/dev"
 	"github.com/airplanedev/cli/pkg/analytics"
+	"github.com/airplanedev/cli/pkg/api"
 	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/codegen"
 	"github.com/airplanedev/cli/pkg/conf"
 	"github.com/airplanedev/cli/pkg/dev"
 	"github.com/airplanedev/cli/pkg/logger"
 	"github.com/airplanedev/cli/pkg/params"
+	"github.com/airplanedev/cli/pkg/print"
 	"github.com/airplanedev/cli/pkg/resource"
 	"github.com/airplanedev/cli/pkg/server"
 	"github.com/airplanedev/cli/pkg/utils"
@@ -41,6 +44,9 @@ type taskDevConfig struct {
 	// If there are multiple tasks a, b in file f (config as code), specifying airplane
 	// dev f::a would set fileOrDir to f and entrypointFunc to a.
 	entrypointFunc string
+	// outputsFile is a path to write the run's outputs to, if set.
+	outputsFile string
+	noTruncate  bool
 
 	// Airplane dev server-related fields
 	editor bool
@@ -142,6 +148,8 @@ func New(c *cli.Config) *cobra.Command {
 	cmd.Flags().StringVar(&cfg.devConfigPath, "config-path", "", "The path to the dev config file to load into the local dev server.")
 	// TODO: Make opening the editor the default behavior.
 	cmd.Flags().BoolVar(&cfg.editor, "editor", false, "Run the local airplane editor")
+	cmd.Flags().BoolVar(&cfg.noTruncate, "no-truncate", false, "Print large outputs and displays in full.")
+	cmd.Flags().StringVar(&cfg.outputsFile, "outputs-file", "", "Write the run's outputs to a file. The format is chosen by the extension (.json, .yaml, .yml or .csv).")
 	return cmd
 }
 
@@ -178,11 +186,12 @@ func run(ctx context.Context, cfg taskDevConfig) error {
 	cfg.root.Client.Host = fmt.Sprintf("127.0.0.1:%d", cfg.port)
 
 	apiServer, err := server.Start(server.Options{
-		CLI:       cfg.root,
-		EnvSlug:   cfg.envSlug,
-		Executor:  localExecutor,
-		Port:      cfg.port,
-		DevConfig: cfg.devConfig,
+		CLI:        cfg.root,
+		EnvSlug:    cfg.envSlug,
+		Executor:   localExecutor,
+		Port:       cfg.port,
+		DevConfig:  cfg.devConfig,
+		NoTruncate: cfg.noTruncate,
 	})
 	if err != nil {
 		return errors.Wrap(err, "starting local dev api server")
@@ -213,6 +222,7 @@ func run(ctx context.Context, cfg taskDevConfig) error {
 	if err != nil {
 		return errors.Wrap(err, "discovering task configs")
 	}
+	regenerateTypes(ctx, l, cfg.root.Client, cfg.envSlug, filepath.Dir(cfg.fileOrDir))
 	taskConfig, err := getLocalDevTaskConfig(taskConfigs, cfg)
 	if err != nil {
 		return err
@@ -261,8 +271,17 @@ func run(ctx context.Context, cfg taskDevConfig) error {
 		EnvSlug:     cfg.envSlug,
 		Env:         envVars,
 		Resources:   resources,
+		NoTruncate:  cfg.noTruncate,
+	}
+	outputs, err := localExecutor.Execute(ctx, localRunConfig)
+	var notStarted dev.NotStartedError
+	if cfg.outputsFile != "" && !errors.As(err, &notStarted) {
+		// Write outputs even if the run failed, since they may help debug the failure.
+		if werr := print.WriteOutputsFile(cfg.outputsFile, outputs); werr != nil {
+			return werr
+		}
+		logger.Log(logger.Gray("Wrote outputs to %s", cfg.outputsFile))
 	}
-	_, err = localExecutor.Execute(ctx, localRunConfig)
 	if err != nil {
 		return errors.Wrap(err, "executing task")
 	}
@@ -317,3 +336,41 @@ func getLocalDevTaskConfig(taskConfigs []discover.TaskConfig, cfg taskDevConfig)
 
 	return discover.TaskConfig{}, errors.New("unable to find specified task in file")
 }
+
+// regenerateTypes rewrites the files that `airplane tasks codegen` generated in dir or the
+// closest of its parents, so that they match the tasks in that directory. Like codegen, tasks
+// that have not been deployed yet are included.
+func regenerateTypes(ctx context.Context, l logger.Logger, client api.APIClient, envSlug, dir string) {
+	genDir, ok := codegen.FindGeneratedDir(dir)
+	if !ok {
+		return
+	}
+	d := &discover.Discoverer{
+		TaskDiscoverers: []discover.TaskDiscoverer{
+			&discover.DefnDiscoverer{
+				Client:             client,
+				Logger:             l,
+				MissingTaskHandler: codegen.IncludeMissingTask,
+			},
+			&discover.CodeTaskDiscoverer{
+				Client:             client,
+				Logger:             l,
+				MissingTaskHandler: codegen.IncludeMissingTask,
+			},
+		},
+		EnvSlug: envSlug,
+		Client:  client,
+	}
+	taskConfigs, _, err := d.Discover(ctx, genDir)
+	if err != nil {
+		l.Warning("Unable to regenerate types: %v", err)
+		return
+	}
+	paths, err := codegen.Regenerate(genDir, codegen.NewTasks(taskConfigs))
+	if err != nil {
+		l.Warning("Unable to regenerate types: %v", err)
+	}
+	for _, path := range paths {
+		l.Debug("Regenerated %s", path)
+	}
+}
@@ -67,6 +67,7 @@ func runLocalDevServer(ctx context.Context, cfg taskDevConfig) error {
 		Port:        cfg.port,
 		Dir:         absoluteDir,
 		AuthInfo:    authInfo,
+		NoTruncate:  cfg.noTruncate,
 	})
 	if err != nil {
 		return errors.Wrap(err, "starting local dev server")
@@ -108,6 +109,7 @@ func runLocalDevServer(ctx context.Context, cfg taskDevConfig) error {
 	if err != nil {
 		return errors.Wrap(err, "discovering task configs")
 	}
+	regenerateTypes(ctx, l, localClient, cfg.envSlug, cfg.fileOrDir)
 
 	// Print out discovered views and tasks to the user
 	taskNoun := "tasks"
@@ -0,0 +1,182 @@
+package diff
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/cmd/airplane/tasks/deploy"
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/airplanedev/cli/pkg/print"
+	"github.com/airplanedev/cli/pkg/utils"
+	libapi "github.com/airplanedev/lib/pkg/api"
+	"github.com/airplanedev/lib/pkg/deploy/discover"
+	"github.com/airplanedev/lib/pkg/deploy/taskdir/definitions"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// driftExitCode is the exit code when a local definition differs from the deployed task. It
+// matches the exit code of `deploy --plan` when the plan contains changes.
+const driftExitCode = 2
+
+type config struct {
+	root   *cli.Config
+	client api.APIClient
+
+	paths   []string
+	envSlug string
+}
+
+// New returns a new diff command.
+func New(c *cli.Config) *cobra.Command {
+	cfg := config{
+		root:   c,
+		client: c.Client,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "diff [paths...]",
+		Short: "Compare local task definitions with deployed tasks",
+		Long: heredoc.Doc(`
+			Compare the task definitions in the given paths with the tasks that are deployed to
+			an environment, without deploying anything.
+
+			Exits with code 2 if any task differs from its deployed version or has not been
+			deployed, so that it can be used to detect drift.
+		`),
+		Example: heredoc.Doc(`
+			airplane tasks diff
+			airplane tasks diff ./my_task.task.yaml --env prod
+			airplane tasks diff my-directory -o json
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.paths = args
+			if len(cfg.paths) == 0 {
+				cfg.paths = []string{"."}
+			}
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment to compare against. Defaults to your team's default environment.")
+
+	return cmd
+}
+
+type Status string
+
+const (
+	StatusNew       Status = "new"
+	StatusChanged   Status = "changed"
+	StatusUnchanged Status = "unchanged"
+)
+
+// TaskDiff is the difference between a local task definition and the deployed task.
+type TaskDiff struct {
+	Slug           string              `json:"slug" yaml:"slug"`
+	Status         Status              `json:"status" yaml:"status"`
+	DefinitionFile string              `json:"definitionFile,omitempty" yaml:"definitionFile,omitempty"`
+	Changes        []utils.FieldChange `json:"changes,omitempty" yaml:"changes,omitempty"`
+
+	// lines is the unified diff that is printed by the table formatter.
+	lines []string
+}
+
+func run(ctx context.Context, cfg config) error {
+	l := logger.NewStdErrLogger(logger.StdErrLoggerOpts{})
+
+	// Tasks that have not been deployed yet are reported as new.
+	missingTaskHandler := func(ctx context.Context, def definitions.DefinitionInterface) (*libapi.TaskMetadata, error) {
+		return &libapi.TaskMetadata{Slug: def.GetSlug()}, nil
+	}
+	d := &discover.Discoverer{
+		TaskDiscoverers: []discover.TaskDiscoverer{
+			&discover.DefnDiscoverer{
+				Client:             cfg.client,
+				Logger:             l,
+				MissingTaskHandler: missingTaskHandler,
+			},
+			&discover.CodeTaskDiscoverer{
+				Client:             cfg.client,
+				Logger:             l,
+				MissingTaskHandler: missingTaskHandler,
+			},
+		},
+		Client:  cfg.client,
+		Logger:  l,
+		EnvSlug: cfg.envSlug,
+	}
+	taskConfigs, _, err := d.Discover(ctx, cfg.paths...)
+	if err != nil {
+		return err
+	}
+
+	diffs := []TaskDiff{}
+	for _, tc := range taskConfigs {
+		td, err := getTaskDiff(ctx, cfg, tc.Def)
+		if err != nil {
+			return err
+		}
+		diffs = append(diffs, td)
+	}
+
+	var drifted int
+	for _, td := range diffs {
+		if td.Status != StatusUnchanged {
+			drifted++
+		}
+	}
+
+	print.Print(diffs, func() {
+		for _, td := range diffs {
+			fmt.Println(logger.Bold(td.Slug), logger.Gray("(%s)", td.Status))
+			for _, line := range td.lines {
+				fmt.Println("  " + line)
+			}
+			fmt.Println()
+		}
+		fmt.Printf("%d of %d task(s) differ from %s.\n", drifted, len(diffs), deploy.EnvName(cfg.envSlug))
+	})
+
+	if drifted > 0 {
+		return utils.ExitCodeError{Code: driftExitCode}
+	}
+	return nil
+}
+
+// getTaskDiff compares a local definition with the task that is deployed to cfg.envSlug.
+func getTaskDiff(ctx context.Context, cfg config, def definitions.DefinitionInterface) (TaskDiff, error) {
+	slug := def.GetSlug()
+	td := TaskDiff{Slug: slug}
+	if path := def.GetDefnFilePath(); path != "" {
+		td.DefinitionFile = deploy.RelPath(path)
+	}
+
+	diff, err := deploy.DiffTask(ctx, cfg.client, cfg.envSlug, def)
+	if err != nil {
+		return TaskDiff{}, errors.Wrapf(err, "comparing %s", slug)
+	}
+	if diff.OldYAML == nil {
+		td.Status = StatusNew
+		td.lines = []string{"(not deployed)"}
+		return td, nil
+	}
+	td.Changes = diff.Changes
+	if len(td.Changes) == 0 {
+		td.Status = StatusUnchanged
+		td.lines = []string{"(no changes)"}
+		return td, nil
+	}
+
+	td.Status = StatusChanged
+	label := strings.TrimPrefix(td.DefinitionFile, "./")
+	if label == "" {
+		label = slug
+	}
+	td.lines = utils.UnifiedDiff("deployed/"+label, "local/"+label, string(diff.OldYAML), string(diff.NewYAML))
+	return td, nil
+}
@@ -0,0 +1,83 @@
+package diff
+
+import (
+	"context"
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/airplanedev/cli/pkg/utils/pointers"
+	libapi "github.com/airplanedev/lib/pkg/api"
+	"github.com/airplanedev/lib/pkg/deploy/taskdir/definitions"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTaskDiff(t *testing.T) {
+	deployed := map[string]libapi.Task{
+		"my_task": {
+			ID:        "my_task",
+			Slug:      "my_task",
+			Name:      "My Task",
+			Kind:      "image",
+			Image:     pointers.String("ubuntu:latest"),
+			Arguments: []string{"echo", "hello world"},
+		},
+	}
+
+	for _, test := range []struct {
+		name           string
+		def            definitions.Definition_0_3
+		expectedStatus Status
+		expected       []utils.FieldChange
+	}{
+		{
+			name: "not deployed",
+			def: definitions.Definition_0_3{
+				Name:  "My Other Task",
+				Slug:  "my_other_task",
+				Image: &definitions.ImageDefinition_0_3{Image: "ubuntu:latest"},
+			},
+			expectedStatus: StatusNew,
+		},
+		{
+			name: "no changes",
+			def: definitions.Definition_0_3{
+				Name: "My Task",
+				Slug: "my_task",
+				Image: &definitions.ImageDefinition_0_3{
+					Image:   "ubuntu:latest",
+					Command: "echo 'hello world'",
+				},
+			},
+			expectedStatus: StatusUnchanged,
+		},
+		{
+			name: "changes",
+			def: definitions.Definition_0_3{
+				Name:        "My Task",
+				Description: "Says hello!",
+				Slug:        "my_task",
+				Image: &definitions.ImageDefinition_0_3{
+					Image:   "ubuntu:22.04",
+					Command: "echo 'hello world'",
+				},
+			},
+			expectedStatus: StatusChanged,
+			expected: []utils.FieldChange{
+				{Path: "description", Old: nil, New: "Says hello!"},
+				{Path: "docker.image", Old: "ubuntu:latest", New: "ubuntu:22.04"},
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			require := require.New(t)
+			cfg := config{client: &api.MockClient{Tasks: deployed}}
+
+			td, err := getTaskDiff(context.Background(), cfg, &test.def)
+			require.NoError(err)
+			require.Equal(test.def.Slug, td.Slug)
+			require.Equal(test.expectedStatus, td.Status)
+			require.Equal(test.expected, td.Changes)
+		})
+	}
+}
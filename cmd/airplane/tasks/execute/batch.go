@@ -0,0 +1,351 @@
+package execute
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/airplanedev/cli/pkg/analytics"
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/airplanedev/cli/pkg/params"
+	libapi "github.com/airplanedev/lib/pkg/api"
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+)
+
+// batchResult is a single line of a batch results file.
+type batchResult struct {
+	// Row is the 1-indexed row of the batch file, not counting a CSV header.
+	Row     int           `json:"row"`
+	RunID   string        `json:"runID,omitempty"`
+	Status  api.RunStatus `json:"status,omitempty"`
+	Outputs *api.Outputs  `json:"outputs,omitempty"`
+	Error   string        `json:"error,omitempty"`
+}
+
+// runBatch executes the task once per row of the batch file.
+func runBatch(ctx context.Context, cfg config, task libapi.Task) error {
+	client := cfg.root.Client
+
+	rows, err := readBatchFile(cfg.batch)
+	if err != nil {
+		return err
+	}
+	paramValues := make([]api.Values, len(rows))
+	for i, row := range rows {
+		paramValues[i], err = batchRowToValues(task.Parameters, row)
+		if err != nil {
+			return errors.Wrapf(err, "row %d", i+1)
+		}
+	}
+
+	resultsPath := cfg.batchResults
+	if resultsPath == "" {
+		resultsPath = strings.TrimSuffix(cfg.batch, filepath.Ext(cfg.batch)) + ".results.jsonl"
+	}
+	completed := map[int]bool{}
+	if _, err := os.Stat(resultsPath); err == nil {
+		if !cfg.resume {
+			return errors.Errorf("results file %s already exists: pass --resume to continue the batch or --results to write elsewhere", resultsPath)
+		}
+		if completed, err = resumeBatchResults(resultsPath); err != nil {
+			return err
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return errors.Wrapf(err, "reading %s", resultsPath)
+	}
+
+	results, err := os.OpenFile(resultsPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "opening %s", resultsPath)
+	}
+	defer results.Close()
+
+	total := len(rows) - len(completed)
+	if len(completed) > 0 {
+		logger.Log("Resuming batch: skipping %d row(s) that already succeeded.", len(completed))
+	}
+	logger.Log("Executing %s task for %d row(s) with a concurrency of %d. Writing results to %s",
+		logger.Bold(task.Name), total, cfg.concurrency, logger.Bold(resultsPath))
+
+	// mu guards results and the progress counters.
+	var mu sync.Mutex
+	var done, succeeded, failed int
+	enc := json.NewEncoder(results)
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(cfg.concurrency)
+	for i := range rows {
+		row := i + 1
+		if completed[row] {
+			continue
+		}
+		values := paramValues[i]
+		g.Go(func() error {
+			result := executeBatchRow(ctx, client, task, cfg.envSlug, values)
+			result.Row = row
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err := enc.Encode(result); err != nil {
+				return errors.Wrapf(err, "writing results to %s", resultsPath)
+			}
+			done++
+			if result.Status == api.RunSucceeded {
+				succeeded++
+			} else {
+				failed++
+			}
+			logger.Log("[%s] row %d: %s %s", logger.Gray("%d/%d", done, total), row, formatBatchStatus(result), logger.Gray(result.RunID))
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	analytics.Track(cfg.root, "Batch Executed", map[string]interface{}{
+		"task_id":    task.ID,
+		"task_name":  task.Name,
+		"num_rows":   total,
+		"num_failed": failed,
+		"env_slug":   cfg.envSlug,
+	})
+
+	logger.Log("")
+	logger.Log("Batch finished: %s succeeded, %s failed.", logger.Green("%d", succeeded), logger.Red("%d", failed))
+	if failed > 0 {
+		logger.Log("Re-run with --resume to retry the rows that did not succeed.")
+		return errors.Errorf("%d run(s) did not succeed", failed)
+	}
+	return nil
+}
+
+// executeBatchRow runs the task once and waits for it to finish. Failures are recorded in the
+// result so that one bad row does not stop the rest of the batch.
+func executeBatchRow(ctx context.Context, client *api.Client, task libapi.Task, envSlug string, values api.Values) batchResult {
+	w, err := client.Watcher(ctx, api.RunTaskRequest{
+		TaskID:      &task.ID,
+		ParamValues: values,
+		EnvSlug:     envSlug,
+	})
+	if err != nil {
+		return batchResult{Error: err.Error()}
+	}
+
+	var state api.RunState
+	for {
+		if state = w.Next(); state.Err() != nil || state.Stopped() {
+			break
+		}
+	}
+	result := batchResult{RunID: w.RunID(), Status: state.Status}
+	if err := state.Err(); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	outputs := state.Outputs
+	result.Outputs = &outputs
+	return result
+}
+
+func formatBatchStatus(r batchResult) string {
+	switch {
+	case r.Error != "":
+		return logger.Red("error: %s", r.Error)
+	case r.Status == api.RunSucceeded:
+		return logger.Green(string(r.Status))
+	default:
+		return logger.Red(string(r.Status))
+	}
+}
+
+// readBatchFile reads a CSV file with a header row, or a JSON Lines file with one object per line.
+func readBatchFile(path string) ([]map[string]interface{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "opening %s", path)
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return readBatchCSV(f)
+	case ".jsonl", ".ndjson":
+		return readBatchJSONL(f)
+	default:
+		return nil, errors.Errorf("unsupported batch file %s: expected a .csv or .jsonl file", path)
+	}
+}
+
+func readBatchCSV(r io.Reader) ([]map[string]interface{}, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, errors.Wrap(err, "reading csv")
+	}
+	if len(records) == 0 {
+		return nil, errors.New("csv file is missing a header row")
+	}
+	header := records[0]
+	var rows []map[string]interface{}
+	for _, record := range records[1:] {
+		row := map[string]interface{}{}
+		for i, column := range header {
+			row[column] = record[i]
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func readBatchJSONL(r io.Reader) ([]map[string]interface{}, error) {
+	var rows []map[string]interface{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var row map[string]interface{}
+		if err := json.Unmarshal([]byte(text), &row); err != nil {
+			return nil, errors.Wrapf(err, "parsing line %d", line)
+		}
+		rows = append(rows, row)
+	}
+	return rows, errors.Wrap(scanner.Err(), "reading jsonl")
+}
+
+func readBatchResults(path string) ([]batchResult, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "opening %s", path)
+	}
+	defer f.Close()
+
+	var results []batchResult
+	dec := json.NewDecoder(f)
+	for {
+		var r batchResult
+		if err := dec.Decode(&r); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, errors.Wrapf(err, "reading %s", path)
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// resumeBatchResults rewrites the results file of a previous attempt at a batch so that it only
+// lists the rows that succeeded, once each, and returns those rows. The rows that are retried
+// are appended to it again.
+func resumeBatchResults(path string) (map[int]bool, error) {
+	prev, err := readBatchResults(path)
+	if err != nil {
+		return nil, err
+	}
+	latest := map[int]batchResult{}
+	for _, r := range prev {
+		latest[r.Row] = r
+	}
+	var rows []int
+	for row, r := range latest {
+		if r.Status == api.RunSucceeded {
+			rows = append(rows, row)
+		}
+	}
+	sort.Ints(rows)
+
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	completed := map[int]bool{}
+	for _, row := range rows {
+		if err := enc.Encode(latest[row]); err != nil {
+			return nil, errors.Wrapf(err, "writing %s", path)
+		}
+		completed[row] = true
+	}
+	// Write to a temporary file first so that the results are not lost if writing fails.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b.Bytes(), 0644); err != nil {
+		return nil, errors.Wrapf(err, "writing %s", tmp)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return nil, errors.Wrapf(err, "writing %s", path)
+	}
+	return completed, nil
+}
+
+// batchRowToValues maps the columns of a row onto the task's parameters. Columns are matched
+// against parameter slugs first, then case-insensitively against parameter names.
+func batchRowToValues(parameters libapi.Parameters, row map[string]interface{}) (api.Values, error) {
+	values := api.Values{}
+	matched := map[string]bool{}
+	for _, p := range parameters {
+		column, ok := findBatchColumn(p, row)
+		if !ok {
+			if !p.Constraints.Optional && p.Default == nil {
+				return nil, errors.Errorf("missing required parameter %s", p.Slug)
+			}
+			continue
+		}
+		matched[column] = true
+
+		var v interface{}
+		switch in := row[column].(type) {
+		case nil:
+			// Nulls in JSON Lines files are treated like empty CSV cells.
+			v = p.Default
+		case string:
+			if in == "" {
+				v = p.Default
+				break
+			}
+			if err := params.ValidateInput(p, in); err != nil {
+				return nil, errors.Wrapf(err, "parameter %s", p.Slug)
+			}
+			var err error
+			if v, err = params.ParseInput(p, in); err != nil {
+				return nil, errors.Wrapf(err, "parameter %s", p.Slug)
+			}
+		default:
+			// JSON Lines values are already typed.
+			v = in
+		}
+		if v == nil {
+			if !p.Constraints.Optional {
+				return nil, errors.Errorf("missing required parameter %s", p.Slug)
+			}
+			continue
+		}
+		values[p.Slug] = v
+	}
+	for column := range row {
+		if !matched[column] {
+			return nil, errors.Errorf("column %q does not match any parameter", column)
+		}
+	}
+	return values, nil
+}
+
+func findBatchColumn(p libapi.Parameter, row map[string]interface{}) (string, bool) {
+	if _, ok := row[p.Slug]; ok {
+		return p.Slug, true
+	}
+	for column := range row {
+		if strings.EqualFold(column, p.Name) {
+			return column, true
+		}
+	}
+	return "", false
+}
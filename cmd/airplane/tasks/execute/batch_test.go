@@ -0,0 +1,135 @@
+package execute
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/api"
+	libapi "github.com/airplanedev/lib/pkg/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadBatchCSV(t *testing.T) {
+	require := require.New(t)
+
+	rows, err := readBatchCSV(strings.NewReader("name,Count\nalice,1\nbob,\n"))
+	require.NoError(err)
+	require.Equal([]map[string]interface{}{
+		{"name": "alice", "Count": "1"},
+		{"name": "bob", "Count": ""},
+	}, rows)
+
+	_, err = readBatchCSV(strings.NewReader(""))
+	require.EqualError(err, "csv file is missing a header row")
+}
+
+func TestReadBatchJSONL(t *testing.T) {
+	require := require.New(t)
+
+	rows, err := readBatchJSONL(strings.NewReader("{\"name\": \"alice\", \"count\": 1}\n\n{\"name\": \"bob\", \"count\": null}\n"))
+	require.NoError(err)
+	require.Equal([]map[string]interface{}{
+		{"name": "alice", "count": float64(1)},
+		{"name": "bob", "count": nil},
+	}, rows)
+
+	_, err = readBatchJSONL(strings.NewReader("{\"name\": \"alice\"}\nnot json\n"))
+	require.Error(err)
+	require.Contains(err.Error(), "parsing line 2")
+}
+
+func TestBatchRowToValues(t *testing.T) {
+	parameters := libapi.Parameters{
+		{Slug: "name", Name: "Name", Type: libapi.TypeString},
+		{Slug: "count", Name: "Count", Type: libapi.TypeInteger, Default: 5},
+		{Slug: "dry_run", Name: "Dry run", Type: libapi.TypeBoolean, Constraints: libapi.Constraints{Optional: true}},
+	}
+
+	for _, test := range []struct {
+		name   string
+		row    map[string]interface{}
+		values api.Values
+		err    string
+	}{
+		{
+			name:   "slugs",
+			row:    map[string]interface{}{"name": "alice", "count": "2", "dry_run": "yes"},
+			values: api.Values{"name": "alice", "count": 2, "dry_run": true},
+		},
+		{
+			name:   "names",
+			row:    map[string]interface{}{"NAME": "alice", "Dry Run": "no"},
+			values: api.Values{"name": "alice", "dry_run": false},
+		},
+		{
+			name:   "empty cells",
+			row:    map[string]interface{}{"name": "alice", "count": "", "dry_run": ""},
+			values: api.Values{"name": "alice", "count": 5},
+		},
+		{
+			name:   "typed values",
+			row:    map[string]interface{}{"name": "alice", "count": float64(3), "dry_run": nil},
+			values: api.Values{"name": "alice", "count": float64(3)},
+		},
+		{
+			name: "missing required column",
+			row:  map[string]interface{}{"count": "2"},
+			err:  "missing required parameter name",
+		},
+		{
+			name: "empty required cell",
+			row:  map[string]interface{}{"name": ""},
+			err:  "missing required parameter name",
+		},
+		{
+			name: "null required value",
+			row:  map[string]interface{}{"name": nil},
+			err:  "missing required parameter name",
+		},
+		{
+			name: "invalid value",
+			row:  map[string]interface{}{"name": "alice", "count": "two"},
+			err:  "parameter count: invalid integer",
+		},
+		{
+			name: "unknown column",
+			row:  map[string]interface{}{"name": "alice", "colour": "red"},
+			err:  `column "colour" does not match any parameter`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			values, err := batchRowToValues(parameters, test.row)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.values, values)
+		})
+	}
+}
+
+func TestResumeBatchResults(t *testing.T) {
+	require := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "batch.results.jsonl")
+	require.NoError(os.WriteFile(path, []byte(strings.Join([]string{
+		`{"row":3,"runID":"run3","status":"Succeeded"}`,
+		`{"row":1,"runID":"run1","status":"Failed"}`,
+		`{"row":2,"error":"timeout"}`,
+		`{"row":1,"runID":"run1b","status":"Succeeded"}`,
+	}, "\n")+"\n"), 0644))
+
+	completed, err := resumeBatchResults(path)
+	require.NoError(err)
+	require.Equal(map[int]bool{1: true, 3: true}, completed)
+
+	results, err := readBatchResults(path)
+	require.NoError(err)
+	require.Equal([]batchResult{
+		{Row: 1, RunID: "run1b", Status: api.RunSucceeded},
+		{Row: 3, RunID: "run3", Status: api.RunSucceeded},
+	}, results)
+}
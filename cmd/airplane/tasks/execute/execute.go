@@ -28,6 +28,15 @@ type config struct {
 	task    string
 	args    []string
 	envSlug string
+	// outputsFile is a path to write the run's outputs to, if set.
+	outputsFile string
+	noTruncate  bool
+
+	// batch is a CSV or JSON Lines file with one set of parameter values per row.
+	batch        string
+	batchResults string
+	concurrency  int
+	resume       bool
 }
 
 // New returns a new execute cobra command.
@@ -43,6 +52,7 @@ func New(c *cli.Config) *cobra.Command {
 			airplane execute ./task.js [-- <parameters...>]
 			airplane execute hello_world [-- <parameters...>]
 			airplane execute ./airplane.yml [-- <parameters...>]
+			airplane execute hello_world --batch rows.csv --concurrency 10
 		`),
 		PersistentPreRunE: utils.WithParentPersistentPreRunE(func(cmd *cobra.Command, args []string) error {
 			return login.EnsureLoggedIn(cmd.Root().Context(), c)
@@ -69,6 +79,15 @@ func New(c *cli.Config) *cobra.Command {
 	// Unhide this flag once we release environments.
 	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment to query. Defaults to your team's default environment.")
 
+	cmd.Flags().StringVar(&cfg.outputsFile, "outputs-file", "", "Write the run's outputs to a file. The format is chosen by the extension (.json, .yaml, .yml or .csv).")
+
+	cmd.Flags().BoolVar(&cfg.noTruncate, "no-truncate", false, "Print large outputs and displays in full.")
+
+	cmd.Flags().StringVar(&cfg.batch, "batch", "", "A .csv or .jsonl file of parameter values. The task is executed once per row.")
+	cmd.Flags().StringVar(&cfg.batchResults, "results", "", "Where to write per-row results of a --batch execution. Defaults to <batch>.results.jsonl.")
+	cmd.Flags().IntVar(&cfg.concurrency, "concurrency", 5, "The maximum number of runs to execute at once with --batch.")
+	cmd.Flags().BoolVar(&cfg.resume, "resume", false, "Resume a --batch execution, skipping rows that already succeeded.")
+
 	return cmd
 }
 
@@ -108,6 +127,16 @@ func run(ctx context.Context, cfg config) error {
 		EnvSlug:     cfg.envSlug,
 	}
 
+	if cfg.batch != "" {
+		if len(cfg.args) > 0 {
+			return errors.New("parameters cannot be passed as arguments with --batch")
+		}
+		if cfg.concurrency < 1 {
+			return errors.New("--concurrency must be at least 1")
+		}
+		return runBatch(ctx, cfg, task)
+	}
+
 	logger.Log("Executing %s task: %s", logger.Bold(task.Name), logger.Gray(client.TaskURL(task.Slug, cfg.envSlug)))
 
 	req.ParamValues, err = params.CLI(cfg.args, task.Name, task.Parameters)
@@ -153,7 +182,19 @@ func run(ctx context.Context, cfg config) error {
 		return err
 	}
 
-	print.Outputs(state.Outputs)
+	truncation := print.NewTruncation(cfg.noTruncate)
+	if displays, err := client.ListDisplays(ctx, w.RunID()); err != nil {
+		logger.Debug("failed to list displays: %v", err)
+	} else {
+		print.Displays(displays.Displays, truncation)
+	}
+	print.Outputs(state.Outputs, truncation)
+	if cfg.outputsFile != "" {
+		if err := print.WriteOutputsFile(cfg.outputsFile, state.Outputs); err != nil {
+			return err
+		}
+		logger.Log(logger.Gray("Wrote outputs to %s", cfg.outputsFile))
+	}
 
 	analytics.Track(cfg.root, "Run Executed", map[string]interface{}{
 		"task_id":   task.ID,
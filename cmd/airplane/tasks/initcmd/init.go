@@ -261,12 +261,12 @@ func initWithTaskDef(ctx context.Context, cfg config) error {
 			query, err := def.SQL.GetQuery()
 			if err != nil {
 				// Create a generic entrypoint.
-				if err := createEntrypoint(r, entrypoint, nil); err != nil {
+				if err := CreateEntrypoint(r, entrypoint, nil); err != nil {
 					return errors.Wrapf(err, "unable to create entrypoint")
 				}
 			} else {
 				// Write the query to the entrypoint.
-				if err := writeEntrypoint(entrypoint, []byte(query), 0644); err != nil {
+				if err := WriteEntrypoint(entrypoint, []byte(query), 0644); err != nil {
 					return errors.Wrapf(err, "unable to create entrypoint")
 				}
 			}
@@ -279,7 +279,7 @@ func initWithTaskDef(ctx context.Context, cfg config) error {
 		} else {
 			// Create entrypoint, without comment link, if it doesn't exist.
 			if !fsx.Exists(entrypoint) {
-				if err := createEntrypoint(r, entrypoint, nil); err != nil {
+				if err := CreateEntrypoint(r, entrypoint, nil); err != nil {
 					return errors.Wrapf(err, "unable to create entrypoint")
 				}
 				logger.Step("Created %s", entrypoint)
@@ -451,7 +451,7 @@ func initCodeOnly(ctx context.Context, cfg config) error {
 		return nil
 	}
 
-	if err := createEntrypoint(r, cfg.file, &task); err != nil {
+	if err := CreateEntrypoint(r, cfg.file, &task); err != nil {
 		return err
 	}
 	logger.Step("Created %s", cfg.file)
@@ -556,15 +556,8 @@ func patch(slug, file string) (ok bool, err error) {
 }
 
 func promptForEntrypoint(slug string, kind build.TaskKind, defaultEntrypoint string, cfg config) (string, error) {
-	exts := runtime.SuggestExts(kind)
 	if defaultEntrypoint == "" {
-		defaultEntrypoint = slug
-		if kind == build.TaskKindNode && len(exts) > 1 {
-			// Special case node tasks and make their extensions '.ts'
-			defaultEntrypoint += ".ts"
-		} else {
-			defaultEntrypoint += exts[0]
-		}
+		defaultEntrypoint = slug + DefaultEntrypointExt(kind)
 
 		if cwdIsHome, err := cwdIsHome(); err != nil {
 			return "", err
@@ -771,13 +764,29 @@ func cwdIsHome() (bool, error) {
 	return cwd == home, nil
 }
 
-func createEntrypoint(r runtime.Interface, entrypoint string, task *libapi.Task) error {
+// DefaultEntrypointExt returns the extension of new entrypoints for tasks of the given kind.
+func DefaultEntrypointExt(kind build.TaskKind) string {
+	exts := runtime.SuggestExts(kind)
+	switch {
+	case kind == build.TaskKindNode && len(exts) > 1:
+		// Special case node tasks and make their extensions '.ts'
+		return ".ts"
+	case len(exts) > 0:
+		return exts[0]
+	default:
+		return ""
+	}
+}
+
+// CreateEntrypoint writes the code that the runtime generates for a new task to entrypoint. If
+// task is set, the code uses its parameters.
+func CreateEntrypoint(r runtime.Interface, entrypoint string, task *libapi.Task) error {
 	code, fileMode, err := r.Generate(apiTaskToRuntimeTask(task))
 	if err != nil {
 		return err
 	}
 
-	return writeEntrypoint(entrypoint, code, fileMode)
+	return WriteEntrypoint(entrypoint, code, fileMode)
 }
 
 func createInlineEntrypoint(r runtime.Interface, entrypoint string, def *definitions.Definition_0_3) error {
@@ -786,7 +795,7 @@ func createInlineEntrypoint(r runtime.Interface, entrypoint string, def *definit
 		return err
 	}
 
-	return writeEntrypoint(entrypoint, code, fileMode)
+	return WriteEntrypoint(entrypoint, code, fileMode)
 }
 
 func modifyEntrypointForInline(kind build.TaskKind, entrypoint string) string {
@@ -803,7 +812,8 @@ func modifyEntrypointForInline(kind build.TaskKind, entrypoint string) string {
 	return entrypoint
 }
 
-func writeEntrypoint(path string, b []byte, fileMode os.FileMode) error {
+// WriteEntrypoint writes an entrypoint, creating its directory if needed.
+func WriteEntrypoint(path string, b []byte, fileMode os.FileMode) error {
 	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return err
 	}
@@ -0,0 +1,146 @@
+package lint
+
+import (
+	"context"
+	"os"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/ci"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/airplanedev/cli/pkg/policy"
+	"github.com/airplanedev/cli/pkg/print"
+	"github.com/airplanedev/cli/pkg/utils"
+	libapi "github.com/airplanedev/lib/pkg/api"
+	"github.com/airplanedev/lib/pkg/deploy/discover"
+	"github.com/airplanedev/lib/pkg/deploy/taskdir/definitions"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	root *cli.Config
+
+	paths      []string
+	configPath string
+	envSlug    string
+}
+
+// New returns a new lint command.
+func New(c *cli.Config) *cobra.Command {
+	cfg := config{
+		root: c,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "lint [paths...]",
+		Short: "Check task definitions against policies",
+		Long: heredoc.Doc(`
+			Check task definitions against the built-in policy rules. Rules are configured by the
+			closest airplane.policy.yaml in the working directory or its parents, e.g.:
+
+			  rules:
+			    description-required: error
+			    timeout-required: off
+			  approvedResources:
+			    - db
+
+			Without a config file, every rule is reported as a warning at most.
+
+			Exits with a non-zero code if any finding has error severity.
+		`),
+		Example: heredoc.Doc(`
+			airplane tasks lint
+			airplane tasks lint my-directory ./my_task.task.yaml
+			airplane tasks lint -o json
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.paths = args
+			if len(cfg.paths) == 0 {
+				cfg.paths = []string{"."}
+			}
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.configPath, "config", "", "Path to a policy config. Defaults to the closest airplane.policy.yaml.")
+	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment to query. Defaults to your team's default environment.")
+
+	return cmd
+}
+
+func run(ctx context.Context, cfg config) error {
+	l := logger.NewStdErrLogger(logger.StdErrLoggerOpts{})
+	client := cfg.root.Client
+
+	var policyCfg policy.Config
+	var err error
+	if cfg.configPath != "" {
+		policyCfg, err = policy.ReadConfig(cfg.configPath)
+	} else {
+		var wd string
+		if wd, err = os.Getwd(); err != nil {
+			return errors.Wrap(err, "getting working directory")
+		}
+		policyCfg, err = policy.FindConfig(wd)
+	}
+	if err != nil {
+		return err
+	}
+	if policyCfg.Path != "" {
+		l.Debug("Using policy config %s", policyCfg.Path)
+	}
+
+	// Tasks that have not been deployed yet are linted too.
+	missingTaskHandler := func(ctx context.Context, def definitions.DefinitionInterface) (*libapi.TaskMetadata, error) {
+		return &libapi.TaskMetadata{Slug: def.GetSlug()}, nil
+	}
+	d := &discover.Discoverer{
+		TaskDiscoverers: []discover.TaskDiscoverer{
+			&discover.DefnDiscoverer{
+				Client:             client,
+				Logger:             l,
+				MissingTaskHandler: missingTaskHandler,
+			},
+			&discover.CodeTaskDiscoverer{
+				Client:             client,
+				Logger:             l,
+				MissingTaskHandler: missingTaskHandler,
+			},
+		},
+		Client:  client,
+		Logger:  l,
+		EnvSlug: cfg.envSlug,
+	}
+	taskConfigs, _, err := d.Discover(ctx, cfg.paths...)
+	if err != nil {
+		return err
+	}
+
+	findings := []policy.Finding{}
+	for _, tc := range taskConfigs {
+		t, err := policy.NewTask(tc.Def)
+		if err != nil {
+			return err
+		}
+		findings = append(findings, policy.Check(policyCfg, t)...)
+	}
+
+	print.Print(findings, func() {
+		if len(findings) == 0 {
+			l.Log("No policy violations found in %d task(s).", len(taskConfigs))
+			return
+		}
+		policy.Log(l, findings)
+		if ci.InGitHubActions() {
+			if err := ci.WriteGitHubAnnotations(os.Stdout, policy.Annotations(findings)); err != nil {
+				l.Debug("Failed to write annotations: %v", err)
+			}
+		}
+	})
+
+	if policy.HasErrors(findings) {
+		return utils.ExitCodeError{Code: 1}
+	}
+	return nil
+}
@@ -0,0 +1,313 @@
+package pull
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/cmd/airplane/tasks/initcmd"
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/logger"
+	libapi "github.com/airplanedev/lib/pkg/api"
+	"github.com/airplanedev/lib/pkg/build"
+	"github.com/airplanedev/lib/pkg/deploy/taskdir/definitions"
+	"github.com/airplanedev/lib/pkg/runtime"
+	_ "github.com/airplanedev/lib/pkg/runtime/javascript"
+	_ "github.com/airplanedev/lib/pkg/runtime/python"
+	_ "github.com/airplanedev/lib/pkg/runtime/shell"
+	_ "github.com/airplanedev/lib/pkg/runtime/typescript"
+	"github.com/airplanedev/lib/pkg/utils/fsx"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// layoutDir writes each task into its own directory, named after its slug.
+	layoutDir = "dir"
+	// layoutFlat writes every task into the target directory.
+	layoutFlat = "flat"
+)
+
+type config struct {
+	root   *cli.Config
+	client api.APIClient
+
+	slugs   []string
+	dir     string
+	all     bool
+	envSlug string
+	layout  string
+	update  bool
+}
+
+// New returns a new pull command.
+func New(c *cli.Config) *cobra.Command {
+	cfg := config{
+		root:   c,
+		client: c.Client,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "pull [--all | slugs...] <dir>",
+		Short: "Write definition files for deployed tasks",
+		Long: heredoc.Doc(`
+			Write a definition file and an entrypoint for each of the given deployed tasks, e.g.
+			to move tasks that were created in the UI into a git repository.
+
+			Tasks whose definition file already exists are skipped unless --update is set.
+			Entrypoints that already exist are never overwritten, except for SQL queries with
+			--update. The source code of Node, Python and Shell tasks is not stored by Airplane,
+			so their entrypoints are generated from a template.
+		`),
+		Example: heredoc.Doc(`
+			airplane tasks pull --all ./tasks
+			airplane tasks pull my_task my_other_task ./tasks
+			airplane tasks pull --all --env prod --layout flat --update ./tasks
+		`),
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.dir = args[len(args)-1]
+			cfg.slugs = args[:len(args)-1]
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cmd.Flags().BoolVar(&cfg.all, "all", false, "Pull every task in the environment.")
+	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment to pull tasks from. Defaults to your team's default environment.")
+	cmd.Flags().StringVar(&cfg.layout, "layout", layoutDir, "How to lay out the files: dir writes each task into a directory named after its slug, flat writes every task into <dir>.")
+	cmd.Flags().BoolVar(&cfg.update, "update", false, "Overwrite definition files that already exist.")
+
+	return cmd
+}
+
+type pullStatus string
+
+const (
+	pullStatusCreated pullStatus = "created"
+	pullStatusUpdated pullStatus = "updated"
+	pullStatusSkipped pullStatus = "skipped"
+	pullStatusFailed  pullStatus = "failed"
+)
+
+// pullResult is the outcome of pulling a single task.
+type pullResult struct {
+	slug   string
+	status pullStatus
+	// reason explains why a task was skipped or could not be pulled.
+	reason string
+	// generated is true if the entrypoint was generated from a template.
+	generated bool
+}
+
+func run(ctx context.Context, cfg config) error {
+	switch {
+	case cfg.all && len(cfg.slugs) > 0:
+		return errors.New("Cannot specify both --all and task slugs")
+	case !cfg.all && len(cfg.slugs) == 0:
+		return errors.New("Specify the slugs of the tasks to pull, or --all")
+	case cfg.layout != layoutDir && cfg.layout != layoutFlat:
+		return errors.Errorf("Unknown --layout %q: expected %s or %s", cfg.layout, layoutDir, layoutFlat)
+	}
+
+	l := logger.NewStdErrLogger(logger.StdErrLoggerOpts{WithLoader: true})
+	defer l.StopLoader()
+
+	tasks, err := getTasks(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	if len(tasks) == 0 {
+		l.Log("No tasks to pull")
+		return nil
+	}
+
+	// entrypoints maps the entrypoints that have been written to the slug of their task, so that
+	// tasks cannot overwrite each other's entrypoints with --layout flat.
+	entrypoints := map[string]string{}
+	var results []pullResult
+	for _, task := range tasks {
+		r, err := pullTask(ctx, cfg, l, task, entrypoints)
+		if err != nil {
+			return err
+		}
+		results = append(results, r)
+	}
+
+	printSummary(l, results)
+	return nil
+}
+
+// getTasks returns the tasks to pull, sorted by slug.
+func getTasks(ctx context.Context, cfg config) ([]libapi.Task, error) {
+	var tasks []libapi.Task
+	if cfg.all {
+		resp, err := cfg.client.ListTasks(ctx, cfg.envSlug)
+		if err != nil {
+			return nil, errors.Wrap(err, "listing tasks")
+		}
+		tasks = resp.Tasks
+	} else {
+		for _, slug := range cfg.slugs {
+			task, err := cfg.client.GetTask(ctx, libapi.GetTaskRequest{
+				Slug:    slug,
+				EnvSlug: cfg.envSlug,
+			})
+			if err != nil {
+				return nil, errors.Wrapf(err, "getting task %s", slug)
+			}
+			tasks = append(tasks, task)
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].Slug < tasks[j].Slug
+	})
+	return tasks, nil
+}
+
+// pullTask writes the definition file and entrypoint of a task. Tasks that cannot be represented
+// as code are reported in the result rather than returned as an error.
+func pullTask(ctx context.Context, cfg config, l logger.Logger, task libapi.Task, entrypoints map[string]string) (pullResult, error) {
+	r := pullResult{slug: task.Slug}
+	fail := func(reason string, args ...interface{}) (pullResult, error) {
+		r.status = pullStatusFailed
+		r.reason = fmt.Sprintf(reason, args...)
+		return r, nil
+	}
+
+	if task.Runtime == build.TaskRuntimeWorkflow {
+		return fail("workflow tasks are defined inline in their code, which is not stored by Airplane")
+	}
+	def, err := definitions.NewDefinitionFromTask_0_3(ctx, cfg.client, task)
+	if err != nil {
+		return fail("%v", err)
+	}
+	kind, err := def.Kind()
+	if err != nil {
+		return fail("%v", err)
+	}
+
+	dir := cfg.dir
+	if cfg.layout == layoutDir {
+		dir = filepath.Join(cfg.dir, task.Slug)
+	}
+	defnPath := filepath.Join(dir, task.Slug+".task.yaml")
+	r.status = pullStatusCreated
+	if fsx.Exists(defnPath) {
+		if !cfg.update {
+			r.status = pullStatusSkipped
+			r.reason = fmt.Sprintf("%s already exists", defnPath)
+			return r, nil
+		}
+		r.status = pullStatusUpdated
+	}
+
+	entrypoint, err := def.Entrypoint()
+	if err == definitions.ErrNoEntrypoint {
+		// Nothing to write besides the definition file.
+	} else if err != nil {
+		return fail("%v", err)
+	} else {
+		base := filepath.Base(entrypoint)
+		if strings.HasSuffix(base, ".view.tsx") || strings.HasSuffix(base, ".view.jsx") {
+			return fail("task was deployed from the view file %s: use `airplane views init` instead", base)
+		}
+		if entrypoint == "" {
+			base = task.Slug + initcmd.DefaultEntrypointExt(kind)
+		}
+		entrypointPath := filepath.Join(dir, base)
+		if other, ok := entrypoints[entrypointPath]; ok {
+			return fail("entrypoint %s is also used by %s: use --layout %s", entrypointPath, other, layoutDir)
+		}
+		entrypoints[entrypointPath] = task.Slug
+
+		r.generated, err = writeEntrypoint(cfg, l, def, kind, task, entrypointPath)
+		if err != nil {
+			return pullResult{}, err
+		}
+		// The entrypoint is written next to the definition file.
+		if err := def.SetEntrypoint(base); err != nil {
+			return pullResult{}, err
+		}
+	}
+
+	buf, err := def.GenerateCommentedFile(definitions.GetTaskDefFormat(defnPath))
+	if err != nil {
+		return fail("%v", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return pullResult{}, errors.Wrapf(err, "creating %s", dir)
+	}
+	if err := os.WriteFile(defnPath, buf, 0644); err != nil {
+		return pullResult{}, errors.Wrapf(err, "writing %s", defnPath)
+	}
+	l.Step("Wrote %s", defnPath)
+	return r, nil
+}
+
+// writeEntrypoint writes the entrypoint of a task if it does not exist yet. SQL queries are
+// stored by Airplane, so they are also rewritten with --update. It returns true if the entrypoint
+// was generated from a template.
+func writeEntrypoint(cfg config, l logger.Logger, def definitions.Definition_0_3, kind build.TaskKind, task libapi.Task, path string) (bool, error) {
+	if kind == build.TaskKindSQL {
+		if fsx.Exists(path) && !cfg.update {
+			return false, nil
+		}
+		query, err := def.SQL.GetQuery()
+		if err != nil {
+			return false, errors.Wrapf(err, "getting query of %s", task.Slug)
+		}
+		if err := initcmd.WriteEntrypoint(path, []byte(query), 0644); err != nil {
+			return false, errors.Wrapf(err, "writing %s", path)
+		}
+		l.Step("Wrote %s", path)
+		return false, nil
+	}
+
+	if fsx.Exists(path) {
+		return false, nil
+	}
+	r, err := runtime.Lookup(path, kind)
+	if err != nil {
+		return false, errors.Wrapf(err, "unable to generate %s", path)
+	}
+	if err := initcmd.CreateEntrypoint(r, path, &task); err != nil {
+		return false, errors.Wrapf(err, "generating %s", path)
+	}
+	l.Step("Wrote %s", path)
+	return true, nil
+}
+
+func printSummary(l logger.Logger, results []pullResult) {
+	counts := map[pullStatus]int{}
+	var generated int
+	for _, r := range results {
+		counts[r.status]++
+		if r.generated {
+			generated++
+		}
+	}
+
+	l.Log("")
+	l.Log("Pulled %d task(s): %d created, %d updated, %d skipped, %d failed.",
+		len(results), counts[pullStatusCreated], counts[pullStatusUpdated], counts[pullStatusSkipped], counts[pullStatusFailed])
+	if counts[pullStatusSkipped] > 0 {
+		l.Log("Use --update to overwrite existing definition files.")
+	}
+	if generated > 0 {
+		l.Log("%d entrypoint(s) were generated from a template. Replace them with the source code of the task before deploying.", generated)
+	}
+	if counts[pullStatusFailed] > 0 {
+		l.Log("")
+		l.Warning("These tasks cannot be represented as code:")
+		for _, r := range results {
+			if r.status == pullStatusFailed {
+				l.Log("- %s: %s", logger.Bold(r.slug), r.reason)
+			}
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package pull
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/airplanedev/cli/pkg/utils/pointers"
+	libapi "github.com/airplanedev/lib/pkg/api"
+	"github.com/airplanedev/lib/pkg/build"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullTask(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	task := libapi.Task{
+		ID:        "tsk123",
+		Slug:      "my_task",
+		Name:      "My Task",
+		Kind:      "image",
+		Image:     pointers.String("ubuntu:latest"),
+		Arguments: []string{"echo", "hello world"},
+	}
+	cfg := config{
+		client: &api.MockClient{Tasks: map[string]libapi.Task{"my_task": task}},
+		dir:    t.TempDir(),
+		layout: layoutDir,
+	}
+	l := &logger.MockLogger{}
+	defnPath := filepath.Join(cfg.dir, "my_task", "my_task.task.yaml")
+
+	r, err := pullTask(ctx, cfg, l, task, map[string]string{})
+	require.NoError(err)
+	require.Equal(pullStatusCreated, r.status)
+	buf, err := os.ReadFile(defnPath)
+	require.NoError(err)
+	require.Contains(string(buf), "slug: my_task")
+	require.Contains(string(buf), "image: ubuntu:latest")
+
+	// Existing definition files are skipped unless --update is set.
+	require.NoError(os.WriteFile(defnPath, []byte("edited"), 0644))
+	r, err = pullTask(ctx, cfg, l, task, map[string]string{})
+	require.NoError(err)
+	require.Equal(pullStatusSkipped, r.status)
+	buf, err = os.ReadFile(defnPath)
+	require.NoError(err)
+	require.Equal("edited", string(buf))
+
+	cfg.update = true
+	r, err = pullTask(ctx, cfg, l, task, map[string]string{})
+	require.NoError(err)
+	require.Equal(pullStatusUpdated, r.status)
+	buf, err = os.ReadFile(defnPath)
+	require.NoError(err)
+	require.Contains(string(buf), "slug: my_task")
+
+	// Workflows cannot be represented as code.
+	task.Runtime = build.TaskRuntimeWorkflow
+	r, err = pullTask(ctx, cfg, l, task, map[string]string{})
+	require.NoError(err)
+	require.Equal(pullStatusFailed, r.status)
+	require.NotEmpty(r.reason)
+}
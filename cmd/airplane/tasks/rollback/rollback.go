@@ -0,0 +1,296 @@
+package rollback
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/cmd/airplane/tasks/deploy"
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/airplanedev/cli/pkg/utils"
+	libapi "github.com/airplanedev/lib/pkg/api"
+	libBuild "github.com/airplanedev/lib/pkg/build"
+	"github.com/airplanedev/lib/pkg/deploy/taskdir/definitions"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// maxDeployments is the number of previous deployments of a task that can be rolled back to
+// without specifying --to.
+const maxDeployments = 20
+
+type config struct {
+	root *cli.Config
+
+	slug    string
+	to      string
+	envSlug string
+
+	assumeYes bool
+	assumeNo  bool
+}
+
+// New returns a new rollback command.
+func New(c *cli.Config) *cobra.Command {
+	cfg := config{
+		root: c,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "rollback <slug>",
+		Short: "Roll back a task to a previously deployed version",
+		Long:  "Redeploys the definition and image of a task from a previous deployment, without rebuilding it.",
+		Example: heredoc.Doc(`
+			airplane tasks rollback my_task
+			airplane tasks rollback my_task --to <deployment-id>
+		`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.slug = args[0]
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.to, "to", "", "The ID of the deployment to roll back to. Defaults to prompting for one of the task's previous deployments.")
+	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment to roll back in. Defaults to your team's default environment.")
+	cmd.Flags().BoolVarP(&cfg.assumeYes, "yes", "y", false, "True to specify automatic yes to prompts.")
+	cmd.Flags().BoolVarP(&cfg.assumeNo, "no", "n", false, "True to specify automatic no to prompts.")
+
+	return cmd
+}
+
+func run(ctx context.Context, cfg config) error {
+	if cfg.assumeYes && cfg.assumeNo {
+		return errors.New("Cannot specify both --yes and --no")
+	}
+	client := cfg.root.Client
+	l := logger.NewStdErrLogger(logger.StdErrLoggerOpts{WithLoader: true})
+	defer l.StopLoader()
+
+	task, err := client.GetTask(ctx, libapi.GetTaskRequest{
+		Slug:    cfg.slug,
+		EnvSlug: cfg.envSlug,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.ListDeployments(ctx, api.ListDeploymentsRequest{
+		EnvSlug:  cfg.envSlug,
+		TaskSlug: cfg.slug,
+		Limit:    maxDeployments + 1,
+	})
+	if err != nil {
+		return errors.Wrap(err, "listing deployments")
+	}
+	current, previous := splitDeployments(resp.Deployments)
+	if current == nil {
+		return errors.Errorf("task %s has no successful deployments", cfg.slug)
+	}
+
+	target, err := chooseDeployment(ctx, client, l, cfg, *current, previous)
+	if err != nil {
+		return err
+	}
+
+	deployed, err := getDeployedTask(ctx, client, target.ID, cfg.slug)
+	if err != nil {
+		return err
+	}
+	def, err := definitions.NewDefinitionFromTask_0_3(ctx, client, deployed)
+	if err != nil {
+		return err
+	}
+
+	if err := printDiff(ctx, client, l, task, deployed, def, target.ID); err != nil {
+		return err
+	}
+
+	if utils.CanPrompt() || cfg.assumeYes || cfg.assumeNo {
+		wasActive := l.StopLoader()
+		ok, err := utils.ConfirmWithAssumptions(fmt.Sprintf("Roll back %s to deployment %s?", cfg.slug, target.ID), cfg.assumeYes, cfg.assumeNo)
+		if wasActive {
+			l.StartLoader()
+		}
+		if err != nil {
+			return err
+		} else if !ok {
+			return nil
+		}
+	}
+
+	taskToDeploy, err := deploy.NewRedeployTask(ctx, client, task.ID, deployed, def, cfg.envSlug)
+	if err != nil {
+		return err
+	}
+	resp, err := client.CreateDeployment(ctx, api.CreateDeploymentRequest{
+		Tasks:   []api.DeployTask{taskToDeploy},
+		EnvSlug: cfg.envSlug,
+	})
+	if err != nil {
+		return errors.Wrap(err, "creating deployment")
+	}
+	l.Log(logger.Purple(fmt.Sprintf("\nView deployment: %s\n", client.DeploymentURL(resp.Deployment.ID, cfg.envSlug))))
+
+	return deploy.WaitForDeployment(ctx, client, l, resp.Deployment.ID)
+}
+
+// getDeployedTask returns a task as it was deployed by a deployment. Tasks that need building
+// must have an image, since they are not rebuilt.
+func getDeployedTask(ctx context.Context, client api.APIClient, deploymentID, slug string) (libapi.Task, error) {
+	d, err := client.GetDeployment(ctx, deploymentID)
+	if err != nil {
+		return libapi.Task{}, errors.Wrap(err, "getting deployment")
+	}
+	for _, t := range d.Tasks {
+		if t.Slug != slug {
+			continue
+		}
+		if ok, err := libBuild.NeedsBuilding(t.Kind); err != nil {
+			return libapi.Task{}, err
+		} else if ok && t.Image == nil {
+			return libapi.Task{}, errors.Errorf("deployment %s has no image for task %s", deploymentID, slug)
+		}
+		return t, nil
+	}
+	return libapi.Task{}, errors.Errorf("deployment %s did not deploy task %s", deploymentID, slug)
+}
+
+// splitDeployments returns the most recent successful deployment, which is the version of the
+// task that is currently deployed, and the successful deployments before it, newest first.
+func splitDeployments(deployments []api.Deployment) (*api.Deployment, []api.Deployment) {
+	var succeeded []api.Deployment
+	for _, d := range deployments {
+		if d.SucceededAt != nil {
+			succeeded = append(succeeded, d)
+		}
+	}
+	if len(succeeded) == 0 {
+		return nil, nil
+	}
+	sort.SliceStable(succeeded, func(i, j int) bool {
+		return succeeded[i].CreatedAt.After(succeeded[j].CreatedAt)
+	})
+	return &succeeded[0], succeeded[1:]
+}
+
+// chooseDeployment returns the deployment to roll back to: either the one passed with --to,
+// or one picked by the user from the previous deployments.
+func chooseDeployment(ctx context.Context, client api.APIClient, l logger.LoggerWithLoader, cfg config, current api.Deployment, previous []api.Deployment) (api.Deployment, error) {
+	if cfg.to != "" {
+		if cfg.to == current.ID {
+			return api.Deployment{}, errors.Errorf("deployment %s is already the current version of %s", cfg.to, cfg.slug)
+		}
+		for _, d := range previous {
+			if d.ID == cfg.to {
+				return d, nil
+			}
+		}
+		// The deployment may be older than the ones we listed.
+		d, err := client.GetDeployment(ctx, cfg.to)
+		if err != nil {
+			return api.Deployment{}, errors.Wrap(err, "getting deployment")
+		}
+		if d.SucceededAt == nil {
+			return api.Deployment{}, errors.Errorf("cannot roll back to deployment %s: %s", d.ID, strings.ToLower(d.Status()))
+		}
+		return d, nil
+	}
+
+	if len(previous) == 0 {
+		return api.Deployment{}, errors.Errorf("task %s has no previous deployments to roll back to", cfg.slug)
+	}
+
+	l.Log("Currently deployed: %s", describeDeployment(current))
+	if !utils.CanPrompt() {
+		l.Log("Previous deployments:")
+		for _, d := range previous {
+			l.Log("  %s", describeDeployment(d))
+		}
+		return api.Deployment{}, errors.New("specify a deployment to roll back to with --to")
+	}
+
+	options := make([]string, len(previous))
+	for i, d := range previous {
+		options[i] = describeDeployment(d)
+	}
+	wasActive := l.StopLoader()
+	var selected int
+	err := survey.AskOne(
+		&survey.Select{
+			Message: "Which deployment do you want to roll back to?",
+			Options: options,
+			Default: options[0],
+		},
+		&selected,
+		survey.WithStdio(os.Stdin, os.Stderr, os.Stderr),
+	)
+	if wasActive {
+		l.StartLoader()
+	}
+	if err != nil {
+		return api.Deployment{}, errors.Wrap(err, "selecting deployment")
+	}
+	return previous[selected], nil
+}
+
+// describeDeployment returns a one-line summary of a deployment and the commit it deployed.
+func describeDeployment(d api.Deployment) string {
+	parts := []string{d.ID, d.CreatedAt.Local().Format("2006-01-02 15:04")}
+	if d.CreatedBy != "" {
+		parts = append(parts, d.CreatedBy)
+	}
+	if g := d.GitMetadata; g != nil && g.CommitHash != "" {
+		commit := g.CommitHash
+		if len(commit) > 7 {
+			commit = commit[:7]
+		}
+		if g.Ref != "" {
+			commit = g.Ref + "@" + commit
+		}
+		if g.IsDirty {
+			commit += " (dirty)"
+		}
+		parts = append(parts, commit)
+		if msg := strings.SplitN(g.CommitMessage, "\n", 2)[0]; msg != "" {
+			parts = append(parts, fmt.Sprintf("%q", msg))
+		}
+	}
+	return strings.Join(parts, "  ")
+}
+
+// printDiff logs the changes that rolling back will make to the task's definition.
+func printDiff(ctx context.Context, client api.APIClient, l logger.LoggerWithLoader, current, target libapi.Task, targetDef definitions.Definition_0_3, deploymentID string) error {
+	currentDef, err := definitions.NewDefinitionFromTask_0_3(ctx, client, current)
+	if err != nil {
+		return err
+	}
+	currentYAML, err := currentDef.Marshal(definitions.DefFormatYAML)
+	if err != nil {
+		return errors.Wrap(err, "marshalling task definition")
+	}
+	targetYAML, err := targetDef.Marshal(definitions.DefFormatYAML)
+	if err != nil {
+		return errors.Wrap(err, "marshalling task definition")
+	}
+
+	label := current.Slug + ".task.yaml"
+	lines := utils.UnifiedDiff("a/"+label+" (current)", "b/"+label+" ("+deploymentID+")", string(currentYAML), string(targetYAML))
+	if len(lines) == 0 {
+		l.Log("(no changes to task definition)")
+	}
+	for _, line := range lines {
+		l.Log("%s", line)
+	}
+	if current.Image != nil && target.Image != nil && *current.Image != *target.Image {
+		l.Log("Image: %s", logger.Red("%s", *current.Image))
+		l.Log("    → %s", logger.Green("%s", *target.Image))
+	}
+	return nil
+}
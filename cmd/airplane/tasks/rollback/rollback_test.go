@@ -0,0 +1,125 @@
+package rollback
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/logger"
+	libapi "github.com/airplanedev/lib/pkg/api"
+	"github.com/airplanedev/lib/pkg/build"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitDeployments(t *testing.T) {
+	require := require.New(t)
+
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		t := start.Add(d)
+		return &t
+	}
+	deployments := []api.Deployment{
+		{ID: "dep1", CreatedAt: start, SucceededAt: at(time.Minute)},
+		{ID: "dep3", CreatedAt: start.Add(2 * time.Hour), FailedAt: at(2*time.Hour + time.Minute)},
+		{ID: "dep2", CreatedAt: start.Add(time.Hour), SucceededAt: at(time.Hour + time.Minute)},
+		{ID: "dep4", CreatedAt: start.Add(3 * time.Hour)},
+	}
+
+	current, previous := splitDeployments(deployments)
+	require.NotNil(current)
+	require.Equal("dep2", current.ID)
+	require.Len(previous, 1)
+	require.Equal("dep1", previous[0].ID)
+
+	current, previous = splitDeployments(deployments[3:])
+	require.Nil(current)
+	require.Empty(previous)
+}
+
+func TestDescribeDeployment(t *testing.T) {
+	require := require.New(t)
+
+	d := api.Deployment{
+		ID:        "dep1",
+		CreatedBy: "usr1",
+		GitMetadata: &api.GitMetadata{
+			CommitHash:    "0123456789abcdef",
+			Ref:           "main",
+			CommitMessage: "Fix the task\n\nLonger description.",
+		},
+	}
+	desc := describeDeployment(d)
+	require.Contains(desc, "dep1")
+	require.Contains(desc, "usr1")
+	require.Contains(desc, "main@0123456")
+	require.Contains(desc, `"Fix the task"`)
+	require.NotContains(desc, "Longer description")
+
+	d.GitMetadata = nil
+	require.NotContains(describeDeployment(d), "@")
+}
+
+func TestGetDeployedTask(t *testing.T) {
+	require := require.New(t)
+
+	image := "us-docker.pkg.dev/airplane/task:abc"
+	client := &api.MockClient{
+		GetDeploymentResponse: &api.Deployment{
+			ID: "dep1",
+			Tasks: []libapi.Task{
+				{Slug: "my_task", Kind: build.TaskKindNode, Image: &image},
+				{Slug: "unbuilt", Kind: build.TaskKindPython},
+				{Slug: "my_sql_task", Kind: build.TaskKindSQL},
+			},
+		},
+	}
+
+	task, err := getDeployedTask(context.Background(), client, "dep1", "my_task")
+	require.NoError(err)
+	require.Equal(&image, task.Image)
+
+	// SQL tasks are not built, so they do not need an image.
+	_, err = getDeployedTask(context.Background(), client, "dep1", "my_sql_task")
+	require.NoError(err)
+
+	_, err = getDeployedTask(context.Background(), client, "dep1", "unbuilt")
+	require.ErrorContains(err, "has no image")
+
+	_, err = getDeployedTask(context.Background(), client, "dep1", "other_task")
+	require.ErrorContains(err, "did not deploy task other_task")
+}
+
+func TestChooseDeployment(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Now()
+	current := api.Deployment{ID: "dep3", SucceededAt: &now}
+	previous := []api.Deployment{{ID: "dep2", SucceededAt: &now}}
+	client := &api.MockClient{
+		Deployments: []api.Deployment{
+			{ID: "dep1", SucceededAt: &now},
+			{ID: "failed", FailedAt: &now},
+		},
+	}
+	choose := func(to string) (api.Deployment, error) {
+		cfg := config{slug: "my_task", to: to}
+		return chooseDeployment(context.Background(), client, &logger.MockLogger{}, cfg, current, previous)
+	}
+
+	d, err := choose("dep2")
+	require.NoError(err)
+	require.Equal("dep2", d.ID)
+
+	// Deployments older than the listed ones are fetched.
+	d, err = choose("dep1")
+	require.NoError(err)
+	require.Equal("dep1", d.ID)
+
+	_, err = choose("dep3")
+	require.ErrorContains(err, "already the current version")
+
+	_, err = choose("failed")
+	require.ErrorContains(err, "cannot roll back to deployment failed: failed")
+}
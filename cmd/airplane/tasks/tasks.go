@@ -3,13 +3,20 @@ package tasks
 import (
 	"github.com/MakeNowJust/heredoc"
 	"github.com/airplanedev/cli/cmd/airplane/auth/login"
+	"github.com/airplanedev/cli/cmd/airplane/tasks/codegen"
+	"github.com/airplanedev/cli/cmd/airplane/tasks/convert"
 	"github.com/airplanedev/cli/cmd/airplane/tasks/deploy"
 	"github.com/airplanedev/cli/cmd/airplane/tasks/dev"
+	"github.com/airplanedev/cli/cmd/airplane/tasks/diff"
 	"github.com/airplanedev/cli/cmd/airplane/tasks/execute"
 	"github.com/airplanedev/cli/cmd/airplane/tasks/get"
 	"github.com/airplanedev/cli/cmd/airplane/tasks/initcmd"
+	"github.com/airplanedev/cli/cmd/airplane/tasks/lint"
 	"github.com/airplanedev/cli/cmd/airplane/tasks/list"
 	"github.com/airplanedev/cli/cmd/airplane/tasks/open"
+	"github.com/airplanedev/cli/cmd/airplane/tasks/pull"
+	"github.com/airplanedev/cli/cmd/airplane/tasks/rollback"
+	"github.com/airplanedev/cli/cmd/airplane/tasks/validate"
 	"github.com/airplanedev/cli/pkg/cli"
 	"github.com/airplanedev/cli/pkg/utils"
 	"github.com/spf13/cobra"
@@ -35,11 +42,18 @@ func New(c *cli.Config) *cobra.Command {
 
 	cmd.AddCommand(deploy.New(c))
 	cmd.AddCommand(list.New(c))
+	cmd.AddCommand(codegen.New(c))
+	cmd.AddCommand(convert.New(c))
 	cmd.AddCommand(dev.New(c))
+	cmd.AddCommand(diff.New(c))
 	cmd.AddCommand(execute.New(c))
 	cmd.AddCommand(get.New(c))
 	cmd.AddCommand(initcmd.New(c))
+	cmd.AddCommand(lint.New(c))
 	cmd.AddCommand(open.New(c))
+	cmd.AddCommand(pull.New(c))
+	cmd.AddCommand(rollback.New(c))
+	cmd.AddCommand(validate.New(c))
 
 	return cmd
 }
@@ -0,0 +1,267 @@
+package validate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/airplanedev/cli/pkg/policy"
+	"github.com/airplanedev/lib/pkg/deploy/discover"
+	"github.com/airplanedev/lib/pkg/deploy/taskdir/definitions"
+	"github.com/airplanedev/lib/pkg/utils/fsx"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// The checks that findings are reported under.
+const (
+	ruleSchema        = "schema"
+	ruleEntrypoint    = "entrypoint"
+	ruleResource      = "resource"
+	ruleDuplicateSlug = "duplicate-slug"
+	ruleView          = "view"
+	ruleDiscovery     = "discovery"
+)
+
+// resourceSet is the set of resources that tasks may attach. A nil set is not checked.
+type resourceSet struct {
+	// local are the resources in the dev config.
+	local map[string]bool
+	// remote are the resources of the environment, with --remote.
+	remote map[string]bool
+}
+
+// check returns a finding if slug is not a known resource. Resources that are missing from the
+// dev config are only a warning offline, since they may exist remotely.
+func (rs resourceSet) check(slug string) (policy.Severity, string, bool) {
+	switch {
+	case rs.remote != nil && !rs.remote[slug] && !rs.local[slug]:
+		return policy.SeverityError, fmt.Sprintf("Resource %s does not exist in the dev config or the environment.", slug), false
+	case rs.remote == nil && rs.local != nil && !rs.local[slug]:
+		return policy.SeverityWarning, fmt.Sprintf("Resource %s is not defined in the dev config. Use --remote to check remote resources.", slug), false
+	}
+	return "", "", true
+}
+
+// validateTaskDefn validates a task definition file against the definition schema, and checks
+// its entrypoint and resources.
+func validateTaskDefn(path string, resources resourceSet, slugs map[string]string) []policy.Finding {
+	finding := func(rule string, severity policy.Severity, line int, msg string, args ...interface{}) policy.Finding {
+		return policy.Finding{
+			Rule:     rule,
+			Severity: severity,
+			File:     path,
+			Line:     line,
+			Message:  fmt.Sprintf(msg, args...),
+		}
+	}
+
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return []policy.Finding{finding(ruleSchema, policy.SeverityError, 0, "Unable to read definition: %v", err)}
+	}
+	// JSON is valid YAML.
+	var doc yaml.Node
+	if err := yaml.Unmarshal(buf, &doc); err != nil {
+		return []policy.Finding{finding(ruleSchema, policy.SeverityError, 0, "Invalid definition: %v", err)}
+	}
+
+	def := definitions.Definition_0_3{}
+	if err := def.Unmarshal(definitions.GetTaskDefFormat(path), buf); err != nil {
+		var schemaErr definitions.ErrSchemaValidation
+		if !errors.As(err, &schemaErr) {
+			return []policy.Finding{finding(ruleSchema, policy.SeverityError, 0, "Invalid definition: %v", err)}
+		}
+		var findings []policy.Finding
+		for _, e := range schemaErr.Errors {
+			findings = append(findings, finding(ruleSchema, policy.SeverityError, fieldLine(&doc, e.Field()), "%s: %s", e.Field(), e.Description()))
+		}
+		return withSlug(findings, slugFromNode(&doc))
+	}
+
+	var findings []policy.Finding
+	if other, ok := slugs[def.GetSlug()]; ok {
+		findings = append(findings, finding(ruleDuplicateSlug, policy.SeverityError, fieldLine(&doc, "slug"), "Task %s is also defined in %s.", def.GetSlug(), other))
+	} else {
+		slugs[def.GetSlug()] = path
+	}
+
+	kind, err := def.Kind()
+	if err != nil {
+		findings = append(findings, finding(ruleSchema, policy.SeverityError, 0, "%v", err))
+		return withSlug(findings, def.GetSlug())
+	}
+	if _, _, err := def.GetKindAndOptions(); err != nil {
+		findings = append(findings, finding(ruleSchema, policy.SeverityError, fieldLine(&doc, string(kind)), "%v", err))
+	}
+
+	entrypoint, err := def.Entrypoint()
+	switch {
+	case err == definitions.ErrNoEntrypoint:
+	case err != nil:
+		findings = append(findings, finding(ruleEntrypoint, policy.SeverityError, fieldLine(&doc, string(kind)), "%v", err))
+	case entrypoint == "":
+		findings = append(findings, finding(ruleEntrypoint, policy.SeverityError, fieldLine(&doc, string(kind)), "Task %s has no entrypoint.", def.GetSlug()))
+	default:
+		// Entrypoints are relative to the definition file.
+		if !filepath.IsAbs(entrypoint) {
+			entrypoint = filepath.Join(filepath.Dir(path), entrypoint)
+		}
+		if !fsx.Exists(entrypoint) {
+			findings = append(findings, finding(ruleEntrypoint, policy.SeverityError, fieldLine(&doc, string(kind)+".entrypoint"), "Entrypoint %s does not exist.", entrypoint))
+		}
+	}
+
+	for _, slug := range policy.ResourceDependencies(&def) {
+		if severity, msg, ok := resources.check(slug); !ok {
+			findings = append(findings, finding(ruleResource, severity, resourceLine(&doc, string(kind), slug), "%s", msg))
+		}
+	}
+	return withSlug(findings, def.GetSlug())
+}
+
+// validateCodeTask checks the resources of a task that is defined in code. Its definition has
+// already been validated by discovering it.
+func validateCodeTask(tc discover.TaskConfig, resources resourceSet, slugs map[string]string) []policy.Finding {
+	slug := tc.Def.GetSlug()
+	var findings []policy.Finding
+	if other, ok := slugs[slug]; ok {
+		findings = append(findings, policy.Finding{
+			Rule:     ruleDuplicateSlug,
+			Severity: policy.SeverityError,
+			Slug:     slug,
+			File:     tc.TaskEntrypoint,
+			Message:  fmt.Sprintf("Task %s is also defined in %s.", slug, other),
+		})
+	} else {
+		slugs[slug] = tc.TaskEntrypoint
+	}
+	for _, r := range policy.ResourceDependencies(tc.Def) {
+		if severity, msg, ok := resources.check(r); !ok {
+			findings = append(findings, policy.Finding{
+				Rule:     ruleResource,
+				Severity: severity,
+				Slug:     slug,
+				File:     tc.TaskEntrypoint,
+				Message:  msg,
+			})
+		}
+	}
+	return findings
+}
+
+// fieldLine returns the line of a field of a YAML document, given a path such as
+// parameters.0.type. If the field does not exist, the line of its closest existing parent is
+// returned.
+func fieldLine(doc *yaml.Node, field string) int {
+	line, _ := findField(doc, field)
+	return line
+}
+
+// findField returns the line of a field of a YAML document, and whether the field exists.
+func findField(doc *yaml.Node, field string) (int, bool) {
+	if doc == nil || len(doc.Content) == 0 {
+		return 0, false
+	}
+	node := doc.Content[0]
+	line := node.Line
+	if field == "" || field == "(root)" {
+		return line, true
+	}
+	for _, part := range strings.Split(field, ".") {
+		switch node.Kind {
+		case yaml.MappingNode:
+			var next *yaml.Node
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == part {
+					line = node.Content[i].Line
+					next = node.Content[i+1]
+					break
+				}
+			}
+			if next == nil {
+				return line, false
+			}
+			node = next
+		case yaml.SequenceNode:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(node.Content) {
+				return line, false
+			}
+			node = node.Content[i]
+			line = node.Line
+		default:
+			return line, false
+		}
+	}
+	return line, true
+}
+
+// resourceLine returns the line that attaches a resource, either as the resource of a SQL or
+// REST task or under resources.
+func resourceLine(doc *yaml.Node, kind, slug string) int {
+	if doc == nil || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return 0
+	}
+	root := doc.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		switch key.Value {
+		case kind:
+			if fieldValue(value, "resource") == slug {
+				return fieldLine(doc, kind+".resource")
+			}
+		case "resources":
+			// Resources are either a list of slugs or a map from alias to slug.
+			for j, v := range value.Content {
+				if value.Kind == yaml.MappingNode && j%2 == 0 {
+					continue
+				}
+				if v.Value == slug {
+					return v.Line
+				}
+			}
+		}
+	}
+	return fieldLine(doc, "resources")
+}
+
+func fieldValue(node *yaml.Node, field string) string {
+	if node.Kind != yaml.MappingNode {
+		return ""
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == field {
+			return node.Content[i+1].Value
+		}
+	}
+	return ""
+}
+
+// slugFromNode returns the slug of a definition that could not be parsed, if any.
+func slugFromNode(doc *yaml.Node) string {
+	if doc == nil || len(doc.Content) == 0 {
+		return ""
+	}
+	return fieldValue(doc.Content[0], "slug")
+}
+
+func withSlug(findings []policy.Finding, slug string) []policy.Finding {
+	for i := range findings {
+		findings[i].Slug = slug
+	}
+	return findings
+}
+
+// sortFindings sorts findings by file and line.
+func sortFindings(findings []policy.Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].File != findings[j].File {
+			return findings[i].File < findings[j].File
+		}
+		return findings[i].Line < findings[j].Line
+	})
+}
@@ -0,0 +1,84 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/policy"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const testDefn = `slug: my_task
+name: My task
+parameters:
+  - slug: name
+    type: shorttext
+  - slug: count
+    type: number
+resources:
+  - db
+sql:
+  resource: warehouse
+  entrypoint: query.sql
+`
+
+func TestFieldLine(t *testing.T) {
+	require := require.New(t)
+
+	var doc yaml.Node
+	require.NoError(yaml.Unmarshal([]byte(testDefn), &doc))
+
+	for _, test := range []struct {
+		field string
+		line  int
+	}{
+		{"(root)", 1},
+		{"slug", 1},
+		{"parameters", 3},
+		{"parameters.1", 6},
+		{"parameters.1.type", 7},
+		// Missing fields fall back to their closest parent.
+		{"parameters.1.default", 6},
+		{"parameters.5.type", 3},
+		{"sql.entrypoint", 12},
+		{"timeout", 1},
+	} {
+		require.Equal(test.line, fieldLine(&doc, test.field), test.field)
+	}
+}
+
+func TestResourceLine(t *testing.T) {
+	require := require.New(t)
+
+	var doc yaml.Node
+	require.NoError(yaml.Unmarshal([]byte(testDefn), &doc))
+
+	require.Equal(9, resourceLine(&doc, "sql", "db"))
+	require.Equal(11, resourceLine(&doc, "sql", "warehouse"))
+	require.Equal(8, resourceLine(&doc, "sql", "unknown"))
+	require.Equal("my_task", slugFromNode(&doc))
+}
+
+func TestResourceSetCheck(t *testing.T) {
+	require := require.New(t)
+
+	// Without a dev config or --remote, resources are not checked.
+	_, _, ok := resourceSet{}.check("db")
+	require.True(ok)
+
+	offline := resourceSet{local: map[string]bool{"db": true}}
+	_, _, ok = offline.check("db")
+	require.True(ok)
+	severity, _, ok := offline.check("warehouse")
+	require.False(ok)
+	require.Equal(policy.SeverityWarning, severity)
+
+	remote := resourceSet{local: map[string]bool{"db": true}, remote: map[string]bool{"warehouse": true}}
+	_, _, ok = remote.check("db")
+	require.True(ok)
+	_, _, ok = remote.check("warehouse")
+	require.True(ok)
+	severity, _, ok = remote.check("unknown")
+	require.False(ok)
+	require.Equal(policy.SeverityError, severity)
+}
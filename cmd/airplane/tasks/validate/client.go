@@ -0,0 +1,113 @@
+package validate
+
+import (
+	"context"
+
+	"github.com/airplanedev/cli/pkg/api"
+	libapi "github.com/airplanedev/lib/pkg/api"
+	"github.com/pkg/errors"
+)
+
+// offlineClient answers the requests that discovery makes without a network connection: every
+// task and view is reported as not deployed yet. Every other request returns an error, since it
+// needs the Airplane API.
+type offlineClient struct{}
+
+var _ api.APIClient = offlineClient{}
+
+// errOffline returns the error of a request that cannot be answered offline.
+func errOffline(request string) error {
+	return errors.Errorf("%s is not available offline", request)
+}
+
+func (offlineClient) GetTask(ctx context.Context, req libapi.GetTaskRequest) (libapi.Task, error) {
+	return libapi.Task{}, &libapi.TaskMissingError{Slug: req.Slug}
+}
+
+func (offlineClient) GetTaskMetadata(ctx context.Context, slug string) (libapi.TaskMetadata, error) {
+	return libapi.TaskMetadata{}, &libapi.TaskMissingError{Slug: slug}
+}
+
+func (offlineClient) ListTasks(ctx context.Context, envSlug string) (api.ListTasksResponse, error) {
+	return api.ListTasksResponse{}, errOffline("listing tasks")
+}
+
+func (offlineClient) CreateTask(ctx context.Context, req api.CreateTaskRequest) (api.CreateTaskResponse, error) {
+	return api.CreateTaskResponse{}, errOffline("creating tasks")
+}
+
+func (offlineClient) UpdateTask(ctx context.Context, req libapi.UpdateTaskRequest) (api.UpdateTaskResponse, error) {
+	return api.UpdateTaskResponse{}, errOffline("updating tasks")
+}
+
+func (offlineClient) TaskURL(slug string, envSlug string) string {
+	return ""
+}
+
+func (offlineClient) ListResources(ctx context.Context, envSlug string) (libapi.ListResourcesResponse, error) {
+	return libapi.ListResourcesResponse{}, errOffline("listing resources")
+}
+
+func (offlineClient) ListResourceMetadata(ctx context.Context) (libapi.ListResourceMetadataResponse, error) {
+	return libapi.ListResourceMetadataResponse{}, nil
+}
+
+func (offlineClient) GetResource(ctx context.Context, req api.GetResourceRequest) (libapi.GetResourceResponse, error) {
+	return libapi.GetResourceResponse{}, errOffline("getting resources")
+}
+
+func (offlineClient) SetConfig(ctx context.Context, req api.SetConfigRequest) error {
+	return errOffline("setting configs")
+}
+
+func (offlineClient) GetConfig(ctx context.Context, req api.GetConfigRequest) (api.GetConfigResponse, error) {
+	return api.GetConfigResponse{}, errOffline("getting configs")
+}
+
+func (offlineClient) GetRegistryToken(ctx context.Context) (api.RegistryTokenResponse, error) {
+	return api.RegistryTokenResponse{}, errOffline("getting a registry token")
+}
+
+func (offlineClient) CreateBuildUpload(ctx context.Context, req libapi.CreateBuildUploadRequest) (libapi.CreateBuildUploadResponse, error) {
+	return libapi.CreateBuildUploadResponse{}, errOffline("uploading builds")
+}
+
+func (offlineClient) GetDeploymentLogs(ctx context.Context, deploymentID string, prevToken string) (api.GetDeploymentLogsResponse, error) {
+	return api.GetDeploymentLogsResponse{}, errOffline("getting deployment logs")
+}
+
+func (offlineClient) GetDeployment(ctx context.Context, id string) (api.Deployment, error) {
+	return api.Deployment{}, errOffline("getting deployments")
+}
+
+func (offlineClient) ListDeployments(ctx context.Context, req api.ListDeploymentsRequest) (api.ListDeploymentsResponse, error) {
+	return api.ListDeploymentsResponse{}, errOffline("listing deployments")
+}
+
+func (offlineClient) CreateDeployment(ctx context.Context, req api.CreateDeploymentRequest) (api.CreateDeploymentResponse, error) {
+	return api.CreateDeploymentResponse{}, errOffline("deploying")
+}
+
+func (offlineClient) CancelDeployment(ctx context.Context, req api.CancelDeploymentRequest) error {
+	return errOffline("cancelling deployments")
+}
+
+func (offlineClient) DeploymentURL(deploymentID string, envSlug string) string {
+	return ""
+}
+
+func (offlineClient) GetView(ctx context.Context, req libapi.GetViewRequest) (libapi.View, error) {
+	return libapi.View{}, &libapi.ViewMissingError{Slug: req.Slug}
+}
+
+func (offlineClient) CreateView(ctx context.Context, req libapi.CreateViewRequest) (libapi.View, error) {
+	return libapi.View{}, errOffline("creating views")
+}
+
+func (offlineClient) CreateDemoDB(ctx context.Context, name string) (string, error) {
+	return "", errOffline("creating a demo database")
+}
+
+func (offlineClient) GetEnv(ctx context.Context, envSlug string) (libapi.GetEnvResponse, error) {
+	return libapi.GetEnvResponse{}, errOffline("getting environments")
+}
@@ -0,0 +1,286 @@
+package validate
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/cmd/airplane/auth/login"
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/ci"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/conf"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/airplanedev/cli/pkg/policy"
+	"github.com/airplanedev/cli/pkg/print"
+	"github.com/airplanedev/cli/pkg/utils"
+	libapi "github.com/airplanedev/lib/pkg/api"
+	"github.com/airplanedev/lib/pkg/deploy/discover"
+	"github.com/airplanedev/lib/pkg/deploy/taskdir/definitions"
+	"github.com/airplanedev/lib/pkg/utils/fsx"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	root *cli.Config
+
+	paths         []string
+	devConfigPath string
+	remote        bool
+	envSlug       string
+}
+
+// New returns a new validate command.
+func New(c *cli.Config) *cobra.Command {
+	cfg := config{
+		root: c,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "validate [paths...]",
+		Short: "Check task and view definitions for errors",
+		Long: heredoc.Doc(`
+			Check the task and view definitions in the given paths for the errors that would
+			otherwise only surface when deploying or running them locally: invalid fields,
+			missing entrypoints and unknown resources.
+
+			Resources are checked against the closest airplane.dev.yaml. Runs offline unless
+			--remote is set, in which case resources are also checked against the resources of
+			an environment.
+
+			Exits with a non-zero code if any definition has errors.
+		`),
+		Example: heredoc.Doc(`
+			airplane tasks validate
+			airplane tasks validate my-directory ./my_task.task.yaml
+			airplane tasks validate --remote --env prod
+			airplane tasks validate -o json
+		`),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// Only the root command's setup runs: logging in is skipped unless remote resources
+			// are checked, so that definitions can be validated offline.
+			root := cmd.Root()
+			if root.PersistentPreRunE != nil {
+				if err := root.PersistentPreRunE(root, args); err != nil {
+					return err
+				}
+			}
+			if cfg.remote {
+				return login.EnsureLoggedIn(root.Context(), c)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg.paths = args
+			if len(cfg.paths) == 0 {
+				cfg.paths = []string{"."}
+			}
+			return run(cmd.Root().Context(), cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.devConfigPath, "config-path", "", "The path to the dev config file to check resources against. Defaults to the closest airplane.dev.yaml.")
+	cmd.Flags().BoolVar(&cfg.remote, "remote", false, "Also check resources against the resources of an environment. Requires logging in.")
+	cmd.Flags().StringVar(&cfg.envSlug, "env", "", "The slug of the environment to check resources against with --remote. Defaults to your team's default environment.")
+
+	return cmd
+}
+
+func run(ctx context.Context, cfg config) error {
+	l := logger.NewStdErrLogger(logger.StdErrLoggerOpts{})
+
+	devConfig, err := loadDevConfig(cfg)
+	if err != nil {
+		return err
+	}
+	resources := resourceSet{}
+	if devConfig != nil {
+		l.Debug("Checking resources against %s", devConfig.Path)
+		resources.local = map[string]bool{}
+		for slug := range devConfig.Resources {
+			resources.local[slug] = true
+		}
+	}
+
+	var client api.APIClient = offlineClient{}
+	if cfg.remote {
+		client = cfg.root.Client
+		resp, err := client.ListResources(ctx, cfg.envSlug)
+		if err != nil {
+			return errors.Wrap(err, "listing resources")
+		}
+		resources.remote = map[string]bool{}
+		for _, r := range resp.Resources {
+			resources.remote[r.Slug] = true
+		}
+	}
+
+	taskDefns, viewDefns, err := findDefinitionFiles(cfg.paths)
+	if err != nil {
+		return err
+	}
+
+	findings := []policy.Finding{}
+	// slugs maps the slug of each task to the file that defines it.
+	slugs := map[string]string{}
+	var numTasks, numViews int
+	for _, path := range taskDefns {
+		numTasks++
+		findings = append(findings, validateTaskDefn(path, resources, slugs)...)
+	}
+
+	viewDiscoverer := &discover.Discoverer{
+		ViewDiscoverers: []discover.ViewDiscoverer{
+			&discover.ViewDefnDiscoverer{
+				Client:             client,
+				Logger:             l,
+				MissingViewHandler: missingViewHandler,
+			},
+		},
+		Client:  client,
+		Logger:  l,
+		EnvSlug: cfg.envSlug,
+	}
+	for _, path := range viewDefns {
+		numViews++
+		// Views are discovered one file at a time, so that an invalid view does not hide the
+		// errors of the others.
+		if _, _, err := viewDiscoverer.Discover(ctx, path); err != nil {
+			findings = append(findings, policy.Finding{
+				Rule:     ruleView,
+				Severity: policy.SeverityError,
+				File:     path,
+				Message:  err.Error(),
+			})
+		}
+	}
+
+	// Tasks and views that are defined in code are validated by discovering them.
+	codeDiscoverer := &discover.Discoverer{
+		TaskDiscoverers: []discover.TaskDiscoverer{
+			&discover.CodeTaskDiscoverer{
+				Client:             client,
+				Logger:             l,
+				MissingTaskHandler: missingTaskHandler,
+			},
+		},
+		ViewDiscoverers: []discover.ViewDiscoverer{
+			&discover.CodeViewDiscoverer{
+				Client:             client,
+				Logger:             l,
+				MissingViewHandler: missingViewHandler,
+			},
+		},
+		Client:  client,
+		Logger:  l,
+		EnvSlug: cfg.envSlug,
+	}
+	taskConfigs, viewConfigs, err := codeDiscoverer.Discover(ctx, cfg.paths...)
+	if err != nil {
+		findings = append(findings, policy.Finding{
+			Rule:     ruleDiscovery,
+			Severity: policy.SeverityError,
+			Message:  err.Error(),
+		})
+	}
+	numViews += len(viewConfigs)
+	for _, tc := range taskConfigs {
+		numTasks++
+		findings = append(findings, validateCodeTask(tc, resources, slugs)...)
+	}
+
+	sortFindings(findings)
+	print.Print(findings, func() {
+		if len(findings) == 0 {
+			l.Log("No problems found in %d task(s) and %d view(s).", numTasks, numViews)
+			return
+		}
+		policy.Log(l, findings)
+		if ci.InGitHubActions() {
+			if err := ci.WriteGitHubAnnotations(os.Stdout, policy.Annotations(findings)); err != nil {
+				l.Debug("Failed to write annotations: %v", err)
+			}
+		}
+	})
+
+	if policy.HasErrors(findings) {
+		return utils.ExitCodeError{Code: 1}
+	}
+	return nil
+}
+
+// loadDevConfig reads --config-path or the closest airplane.dev.yaml to the first path. It
+// returns nil if there is no dev config.
+func loadDevConfig(cfg config) (*conf.DevConfig, error) {
+	path := cfg.devConfigPath
+	if path == "" {
+		dir, err := filepath.Abs(cfg.paths[0])
+		if err != nil {
+			return nil, errors.Wrap(err, "getting absolute path")
+		}
+		if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+			dir = filepath.Dir(dir)
+		}
+		devConfigDir, ok := fsx.Find(dir, conf.DefaultDevConfigFileName)
+		if !ok {
+			return nil, nil
+		}
+		path = filepath.Join(devConfigDir, conf.DefaultDevConfigFileName)
+	}
+
+	devConfig, err := conf.ReadDevConfig(path)
+	if err != nil {
+		if errors.Is(err, conf.ErrMissing) && cfg.devConfigPath == "" {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "reading dev config %s", path)
+	}
+	return devConfig, nil
+}
+
+// findDefinitionFiles returns the task and view definition files in paths, skipping
+// dependencies and hidden directories.
+func findDefinitionFiles(paths []string) (taskDefns []string, viewDefns []string, err error) {
+	for _, p := range paths {
+		p, err := filepath.Abs(p)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "getting absolute path")
+		}
+		err = filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				name := d.Name()
+				if path != p && (name == "node_modules" || strings.HasPrefix(name, ".")) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			switch {
+			case definitions.IsTaskDef(path):
+				taskDefns = append(taskDefns, path)
+			case definitions.IsViewDef(path):
+				viewDefns = append(viewDefns, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "reading %s", p)
+		}
+	}
+	return taskDefns, viewDefns, nil
+}
+
+// Tasks and views do not need to be deployed to be validated.
+func missingTaskHandler(ctx context.Context, def definitions.DefinitionInterface) (*libapi.TaskMetadata, error) {
+	return &libapi.TaskMetadata{Slug: def.GetSlug()}, nil
+}
+
+func missingViewHandler(ctx context.Context, def definitions.ViewDefinition) (*libapi.View, error) {
+	return &libapi.View{Slug: def.Slug}, nil
+}
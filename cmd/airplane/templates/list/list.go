@@ -0,0 +1,73 @@
+package list
+
+import (
+	"os"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/airplanedev/cli/pkg/conf"
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/airplanedev/cli/pkg/print"
+	"github.com/airplanedev/cli/pkg/templates"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+type config struct {
+	dir string
+}
+
+// New returns a new list command.
+func New(c *cli.Config) *cobra.Command {
+	var cfg config
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists local templates",
+		Long: heredoc.Doc(`
+			List the templates in the templates directory, which defaults to $AP_TEMPLATES_DIR or
+			~/.airplane/templates. Each directory or tarball (.tar.gz, .tgz or .tar) in it is a
+			template that can be initialized with ` + "`airplane init --template <slug>`" + `.
+		`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(cfg)
+		},
+	}
+
+	cmd.Flags().StringVar(&cfg.dir, "dir", conf.GetTemplatesDir(), "The directory to list templates from.")
+
+	return cmd
+}
+
+func run(cfg config) error {
+	ts, err := templates.List(cfg.dir)
+	if err != nil {
+		return err
+	}
+	if ts == nil {
+		ts = []templates.Template{}
+	}
+
+	print.Print(ts, func() {
+		if len(ts) == 0 {
+			logger.Log("No templates found in %s", cfg.dir)
+			return
+		}
+		tw := tablewriter.NewWriter(os.Stdout)
+		tw.SetBorder(false)
+		tw.SetAutoFormatHeaders(false)
+		tw.SetAutoWrapText(false)
+		tw.SetHeader([]string{"slug", "name", "variables", "description"})
+		for _, t := range ts {
+			names := make([]string, len(t.Variables))
+			for i, v := range t.Variables {
+				names[i] = v.Name
+			}
+			tw.Append([]string{t.Slug, t.Name, strings.Join(names, ", "), t.Description})
+		}
+		tw.Render()
+	})
+	return nil
+}
@@ -0,0 +1,26 @@
+package templates
+
+import (
+	"github.com/MakeNowJust/heredoc"
+	"github.com/airplanedev/cli/cmd/airplane/templates/list"
+	"github.com/airplanedev/cli/pkg/cli"
+	"github.com/spf13/cobra"
+)
+
+// New returns a new cobra command.
+func New(c *cli.Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "templates",
+		Short:   "Manage local templates",
+		Long:    "Manage local templates",
+		Aliases: []string{"template"},
+		Example: heredoc.Doc(`
+			airplane templates list
+			airplane templates list --dir ./starter-kits
+		`),
+	}
+
+	cmd.AddCommand(list.New(c))
+
+	return cmd
+}
@@ -101,6 +101,7 @@ type APIClient interface {
 
 	GetDeploymentLogs(ctx context.Context, deploymentID string, prevToken string) (res GetDeploymentLogsResponse, err error)
 	GetDeployment(ctx context.Context, id string) (res Deployment, err error)
+	ListDeployments(ctx context.Context, req ListDeploymentsRequest) (res ListDeploymentsResponse, err error)
 	CreateDeployment(ctx context.Context, req CreateDeploymentRequest) (CreateDeploymentResponse, error)
 	CancelDeployment(ctx context.Context, req CancelDeploymentRequest) error
 	DeploymentURL(deploymentID string, envSlug string) string
@@ -317,6 +318,23 @@ func (c Client) GetRun(ctx context.Context, id string) (res GetRunResponse, err
 	return
 }
 
+// GetRunDescendants returns the runs that were spawned by the given run.
+//
+// This is served by the internal API, which the local dev server also implements.
+func (c Client) GetRunDescendants(ctx context.Context, runID string) (res GetRunDescendantsResponse, err error) {
+	q := url.Values{"runID": []string{runID}}
+	err = c.doInternal(ctx, "GET", "/runs/getDescendants?"+q.Encode(), nil, &res)
+	return
+}
+
+// GetInternalRun returns a run by id from the internal API. Unlike GetRun, this endpoint is
+// also served by the local dev server.
+func (c Client) GetInternalRun(ctx context.Context, id string) (res GetRunResponse, err error) {
+	q := url.Values{"id": []string{id}}
+	err = c.doInternal(ctx, "GET", "/runs/get?"+q.Encode(), nil, &res)
+	return
+}
+
 // GetLogs returns the logs by runID and since timestamp.
 func (c Client) GetLogs(ctx context.Context, runID, prevToken string) (res GetLogsResponse, err error) {
 	q := url.Values{"runID": []string{runID}}
@@ -337,6 +355,13 @@ func (c Client) GetOutputs(ctx context.Context, runID string) (res GetOutputsRes
 	return
 }
 
+// ListDisplays returns the displays created by a run.
+func (c Client) ListDisplays(ctx context.Context, runID string) (res ListDisplaysResponse, err error) {
+	q := url.Values{"runID": []string{runID}}
+	err = c.do(ctx, "GET", "/displays/list?"+q.Encode(), nil, &res)
+	return
+}
+
 // GetTask fetches a task by slug. If the slug does not match a task, a *TaskMissingError is returned.
 func (c Client) GetTask(ctx context.Context, req libapi.GetTaskRequest) (res libapi.Task, err error) {
 	err = c.do(ctx, "GET", encodeQueryString("/tasks/get", url.Values{
@@ -443,6 +468,20 @@ func (c Client) GetDeployment(ctx context.Context, id string) (res Deployment, e
 	return
 }
 
+// ListDeployments lists recent deployments.
+func (c Client) ListDeployments(ctx context.Context, req ListDeploymentsRequest) (res ListDeploymentsResponse, err error) {
+	q := url.Values{
+		"envSlug":  []string{req.EnvSlug},
+		"taskSlug": []string{req.TaskSlug},
+		"viewSlug": []string{req.ViewSlug},
+	}
+	if req.Limit > 0 {
+		q.Set("limit", strconv.Itoa(req.Limit))
+	}
+	err = c.do(ctx, "GET", encodeQueryString("/deployments/list", q), nil, &res)
+	return
+}
+
 // CreateBuildUpload creates an Airplane upload and returns metadata about it.
 func (c Client) CreateBuildUpload(ctx context.Context, req libapi.CreateBuildUploadRequest) (res libapi.CreateBuildUploadResponse, err error) {
 	err = c.do(ctx, "POST", "/builds/createUpload", req, &res)
@@ -528,7 +567,16 @@ func (c Client) GetEnv(ctx context.Context, envSlug string) (res libapi.GetEnvRe
 
 // Do sends a request with `method`, `path`, `payload` and `reply`.
 func (c Client) do(ctx context.Context, method, path string, payload, reply interface{}) error {
-	var url = c.scheme() + c.host() + "/v0" + path
+	return c.doWithPrefix(ctx, method, "/v0"+path, payload, reply)
+}
+
+// DoInternal is like do, but sends the request to the internal API.
+func (c Client) doInternal(ctx context.Context, method, path string, payload, reply interface{}) error {
+	return c.doWithPrefix(ctx, method, "/i"+path, payload, reply)
+}
+
+func (c Client) doWithPrefix(ctx context.Context, method, path string, payload, reply interface{}) error {
+	var url = c.scheme() + c.host() + path
 	var body io.Reader
 
 	if payload != nil {
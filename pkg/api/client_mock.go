@@ -14,6 +14,7 @@ type MockClient struct {
 	Views                 map[string]libapi.View
 	Deploys               []CreateDeploymentRequest
 	GetDeploymentResponse *Deployment
+	Deployments           []Deployment
 	Resources             []libapi.Resource
 	Configs               []Config
 	Envs                  map[string]libapi.GetEnvResponse
@@ -140,11 +141,20 @@ func (mc *MockClient) GetDeployment(ctx context.Context, id string) (res Deploym
 	if mc.GetDeploymentResponse != nil {
 		return *mc.GetDeploymentResponse, nil
 	}
+	for _, d := range mc.Deployments {
+		if d.ID == id {
+			return d, nil
+		}
+	}
 	return Deployment{
 		SucceededAt: &time.Time{},
 	}, nil
 }
 
+func (mc *MockClient) ListDeployments(ctx context.Context, req ListDeploymentsRequest) (res ListDeploymentsResponse, err error) {
+	return ListDeploymentsResponse{Deployments: mc.Deployments}, nil
+}
+
 func (mc *MockClient) CreateDeployment(ctx context.Context, req CreateDeploymentRequest) (res CreateDeploymentResponse, err error) {
 	mc.Deploys = append(mc.Deploys, req)
 	return CreateDeploymentResponse{
@@ -88,6 +88,11 @@ type GetOutputsResponse struct {
 	Outputs Outputs `json:"outputs"`
 }
 
+// ListDisplaysResponse represents a list displays response.
+type ListDisplaysResponse struct {
+	Displays []libapi.Display `json:"displays"`
+}
+
 // LogItem represents a log item.
 type LogItem struct {
 	Timestamp time.Time `json:"timestamp"`
@@ -195,6 +200,12 @@ type Run struct {
 	CancelledAt *time.Time `json:"cancelledAt"`
 	CancelledBy *string    `json:"cancelledBy"`
 	EnvSlug     string     `json:"envSlug"`
+	ParentID    string     `json:"parentID"`
+}
+
+// GetRunDescendantsResponse represents a get run descendants response.
+type GetRunDescendantsResponse struct {
+	Descendants []Run `json:"descendants"`
 }
 
 // ListRunsRequest represents a list runs request.
@@ -316,9 +327,22 @@ type GitMetadata struct {
 type GitVendor string
 
 const (
-	GitVendorGitHub GitVendor = "GitHub"
+	GitVendorGitHub      GitVendor = "GitHub"
+	GitVendorGitLab      GitVendor = "GitLab"
+	GitVendorBitbucket   GitVendor = "Bitbucket"
+	GitVendorAzureDevOps GitVendor = "AzureDevOps"
+	GitVendorGitea       GitVendor = "Gitea"
 )
 
+// GitVendors are the git vendors that the API recognizes.
+var GitVendors = []GitVendor{
+	GitVendorGitHub,
+	GitVendorGitLab,
+	GitVendorBitbucket,
+	GitVendorAzureDevOps,
+	GitVendorGitea,
+}
+
 type CreateDeploymentResponse struct {
 	Deployment       Deployment `json:"deployment"`
 	NumTasksUpdated  int        `json:"numTasksUpdated"`
@@ -335,6 +359,49 @@ type Deployment struct {
 	CancelledAt  *time.Time `json:"cancelledAt,omitempty"`
 	FailedAt     *time.Time `json:"failedAt,omitempty"`
 	FailedReason string     `json:"failedReason,omitempty"`
+	// GitMetadata describes the commit that was deployed, if the deployment was created from a
+	// git repository.
+	GitMetadata *GitMetadata `json:"gitMetadata,omitempty"`
+	// Tasks are the tasks as they were deployed by the deployment, including the images they
+	// were built into. They are only returned by GetDeployment.
+	Tasks []libapi.Task `json:"tasks,omitempty"`
+	// Views are the views as they were deployed by the deployment, including the uploads they
+	// were built from. They are only returned by GetDeployment.
+	Views []DeployView `json:"views,omitempty"`
+}
+
+// Status returns whether the deployment is still in progress or how it ended.
+func (d Deployment) Status() string {
+	switch {
+	case d.SucceededAt != nil:
+		return "Succeeded"
+	case d.FailedAt != nil:
+		return "Failed"
+	case d.CancelledAt != nil:
+		return "Cancelled"
+	default:
+		return "Active"
+	}
+}
+
+// Done returns true if the deployment has finished.
+func (d Deployment) Done() bool {
+	return d.SucceededAt != nil || d.FailedAt != nil || d.CancelledAt != nil
+}
+
+// ListDeploymentsRequest represents a list deployments request.
+type ListDeploymentsRequest struct {
+	EnvSlug string
+	Limit   int
+	// TaskSlug, if set, only lists deployments that included the task.
+	TaskSlug string
+	// ViewSlug, if set, only lists deployments that included the view.
+	ViewSlug string
+}
+
+// ListDeploymentsResponse represents a list deployments response.
+type ListDeploymentsResponse struct {
+	Deployments []Deployment `json:"deployments"`
 }
 
 type App struct {
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/logger"
 	libapi "github.com/airplanedev/lib/pkg/api"
 	"github.com/airplanedev/lib/pkg/build"
 	"github.com/airplanedev/lib/pkg/deploy/taskdir/definitions"
@@ -21,6 +22,33 @@ type Request struct {
 	TaskID  string
 	TaskEnv libapi.TaskEnv
 	Shim    bool
+
+	// Logger receives the output of the build. If nil, the output is logged to stderr.
+	Logger logger.Logger
+	// OnStatus is called when the build moves to a new stage. Optional.
+	OnStatus func(Status)
+}
+
+// Status is a stage of a build.
+type Status string
+
+const (
+	StatusBuilding Status = "building"
+	StatusPushing  Status = "pushing"
+)
+
+func (r Request) log(msg string, args ...interface{}) {
+	if r.Logger != nil {
+		r.Logger.Log(msg, args...)
+		return
+	}
+	logger.Log(msg, args...)
+}
+
+func (r Request) setStatus(status Status) {
+	if r.OnStatus != nil {
+		r.OnStatus(status)
+	}
 }
 
 // Response represents a build response.
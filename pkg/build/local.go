@@ -6,7 +6,6 @@ import (
 
 	"github.com/airplanedev/cli/pkg/api"
 	"github.com/airplanedev/cli/pkg/configs"
-	"github.com/airplanedev/cli/pkg/logger"
 	libapi "github.com/airplanedev/lib/pkg/api"
 	"github.com/airplanedev/lib/pkg/build"
 	"github.com/pkg/errors"
@@ -65,13 +64,15 @@ func (d *localBuildCreator) CreateBuild(ctx context.Context, req Request) (*buil
 	}
 	defer b.Close()
 
-	logger.Log("Building...")
+	req.setStatus(StatusBuilding)
+	req.log("Building...")
 	resp, err := b.Build(ctx, req.TaskID, "latest")
 	if err != nil {
 		return nil, errors.Wrap(err, "build")
 	}
 
-	logger.Log("Pushing...")
+	req.setStatus(StatusPushing)
+	req.log("Pushing...")
 	if err := b.Push(ctx, resp.ImageURL); err != nil {
 		return nil, errors.Wrap(err, "push")
 	}
@@ -0,0 +1,74 @@
+// Package ci writes reports in formats that CI systems understand.
+package ci
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type AnnotationLevel string
+
+const (
+	AnnotationNotice  AnnotationLevel = "notice"
+	AnnotationWarning AnnotationLevel = "warning"
+	AnnotationError   AnnotationLevel = "error"
+)
+
+// Annotation is a message that GitHub shows next to a file on pull requests.
+type Annotation struct {
+	Level AnnotationLevel
+	Title string
+	// File is optional. Line is 1-indexed and only used if File is set.
+	File    string
+	Line    int
+	Message string
+}
+
+// WriteGitHubAnnotations writes annotations as GitHub Actions workflow commands.
+func WriteGitHubAnnotations(w io.Writer, annotations []Annotation) error {
+	for _, a := range annotations {
+		var props []string
+		if a.Title != "" {
+			props = append(props, "title="+escapeProperty(a.Title))
+		}
+		if a.File != "" {
+			props = append(props, "file="+escapeProperty(filepath.ToSlash(RelativePath(a.File))))
+			if a.Line > 0 {
+				props = append(props, fmt.Sprintf("line=%d", a.Line))
+			}
+		}
+		if _, err := fmt.Fprintf(w, "::%s %s::%s\n", a.Level, strings.Join(props, ","), escapeData(a.Message)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InGitHubActions returns true if the CLI is running in a GitHub Actions workflow.
+func InGitHubActions() bool {
+	return os.Getenv("GITHUB_ACTIONS") == "true"
+}
+
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// RelativePath returns path relative to the working directory, if it is inside of it. CI
+// systems resolve paths relative to the checkout, which is usually the working directory.
+func RelativePath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(wd, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
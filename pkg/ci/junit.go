@@ -0,0 +1,93 @@
+package ci
+
+import (
+	"encoding/xml"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// JUnitTestSuites is the root element of a JUnit XML report.
+type JUnitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr,omitempty"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     float64          `xml:"time,attr"`
+	Suites   []JUnitTestSuite `xml:"testsuite"`
+}
+
+type JUnitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      float64         `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	TestCases []JUnitTestCase `xml:"testcase"`
+}
+
+type JUnitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Time      float64       `xml:"time,attr"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+	Skipped   *JUnitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type JUnitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// WriteJUnit writes a JUnit XML report to w. Totals are computed from the test cases.
+func WriteJUnit(w io.Writer, report JUnitTestSuites) error {
+	report.Tests, report.Failures, report.Skipped = 0, 0, 0
+	for i := range report.Suites {
+		suite := &report.Suites[i]
+		suite.Tests, suite.Failures, suite.Skipped = len(suite.TestCases), 0, 0
+		for _, tc := range suite.TestCases {
+			if tc.Failure != nil {
+				suite.Failures++
+			}
+			if tc.Skipped != nil {
+				suite.Skipped++
+			}
+		}
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Skipped += suite.Skipped
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return errors.Wrap(err, "encoding JUnit report")
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// WriteJUnitFile writes a JUnit XML report to path.
+func WriteJUnitFile(path string, report JUnitTestSuites) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.Wrap(err, "creating JUnit report")
+	}
+	if err := WriteJUnit(f, report); err != nil {
+		f.Close()
+		return err
+	}
+	return errors.Wrap(f.Close(), "writing JUnit report")
+}
@@ -0,0 +1,222 @@
+// Package codegen generates types for the parameters of tasks, and typed functions that execute
+// them, so that task code does not have to work with untyped maps.
+//
+// Only parameters are typed. Definitions do not describe the outputs of tasks, so the generated
+// functions return whatever the SDK's execute returns.
+package codegen
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	libapi "github.com/airplanedev/lib/pkg/api"
+	"github.com/airplanedev/lib/pkg/deploy/discover"
+	"github.com/airplanedev/lib/pkg/deploy/taskdir/definitions"
+	"github.com/pkg/errors"
+)
+
+type Language string
+
+const (
+	LanguageTypeScript Language = "typescript"
+	LanguagePython     Language = "python"
+)
+
+// Default files that types are written to, relative to the root of the tasks.
+const (
+	TypeScriptFile = "airplane.gen.ts"
+	PythonFile     = "airplane_gen.py"
+)
+
+// header is the first line of every generated file, without the comment prefix. Files that
+// start with it are regenerated by `airplane dev`.
+const header = "Code generated by airplane tasks codegen. DO NOT EDIT."
+
+// Task is a task to generate types for.
+type Task struct {
+	Slug       string
+	Name       string
+	Parameters libapi.Parameters
+}
+
+// NewTasks returns the tasks to generate types for, sorted by slug. Tasks that were discovered
+// more than once are only included once.
+func NewTasks(taskConfigs []discover.TaskConfig) []Task {
+	seen := map[string]bool{}
+	var tasks []Task
+	for _, tc := range taskConfigs {
+		slug := tc.Def.GetSlug()
+		if seen[slug] {
+			continue
+		}
+		seen[slug] = true
+		tasks = append(tasks, Task{
+			Slug:       slug,
+			Name:       tc.Def.GetName(),
+			Parameters: tc.Def.GetParameters(),
+		})
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].Slug < tasks[j].Slug
+	})
+	return tasks
+}
+
+// LanguageFromPath returns the language of a generated file based on its extension.
+func LanguageFromPath(path string) (Language, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ts":
+		return LanguageTypeScript, nil
+	case ".py":
+		return LanguagePython, nil
+	default:
+		return "", errors.Errorf("unable to generate %s: expected a .ts or .py file", path)
+	}
+}
+
+// Generate returns the source of a file in the given language with the types of the tasks.
+func Generate(lang Language, tasks []Task) ([]byte, error) {
+	switch lang {
+	case LanguageTypeScript:
+		return generateTypeScript(tasks), nil
+	case LanguagePython:
+		return generatePython(tasks), nil
+	default:
+		return nil, errors.Errorf("unsupported language %q", lang)
+	}
+}
+
+// Write generates the file at path, choosing the language from its extension.
+func Write(path string, tasks []Task) error {
+	lang, err := LanguageFromPath(path)
+	if err != nil {
+		return err
+	}
+	buf, err := Generate(lang, tasks)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrapf(err, "creating directory for %s", path)
+	}
+	return errors.Wrapf(os.WriteFile(path, buf, 0644), "writing %s", path)
+}
+
+// Regenerate rewrites the generated files in dir, if any, and returns their paths. Files that
+// were not generated by codegen are left untouched.
+func Regenerate(dir string, tasks []Task) ([]string, error) {
+	var paths []string
+	for _, name := range []string{TypeScriptFile, PythonFile} {
+		path := filepath.Join(dir, name)
+		if !IsGenerated(path) {
+			continue
+		}
+		if err := Write(path, tasks); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// FindGeneratedDir returns dir or the closest of its parents that contains a generated file.
+func FindGeneratedDir(dir string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	for {
+		for _, name := range []string{TypeScriptFile, PythonFile} {
+			if IsGenerated(filepath.Join(dir, name)) {
+				return dir, true
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// IncludeMissingTask is a discover.MissingTaskHandler that includes tasks that have not been
+// deployed yet, so that they get types too.
+func IncludeMissingTask(ctx context.Context, def definitions.DefinitionInterface) (*libapi.TaskMetadata, error) {
+	return &libapi.TaskMetadata{Slug: def.GetSlug()}, nil
+}
+
+// IsGenerated returns true if the file at path was generated by codegen.
+func IsGenerated(path string) bool {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	line, _, _ := bytes.Cut(buf, []byte("\n"))
+	return strings.HasSuffix(strings.TrimSpace(string(line)), header)
+}
+
+// pascalCase converts a slug such as my_task into an identifier such as MyTask.
+func pascalCase(slug string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range slug {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	s := b.String()
+	if s == "" || unicode.IsDigit(rune(s[0])) {
+		s = "Task" + s
+	}
+	return s
+}
+
+// snakeCase converts a slug into an identifier such as my_task.
+func snakeCase(slug string) string {
+	s := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return '_'
+	}, slug)
+	if s == "" || unicode.IsDigit(rune(s[0])) {
+		s = "task_" + s
+	}
+	return s
+}
+
+// options returns the allowed values of a parameter, or nil if they are unrestricted or cannot
+// be expressed as literal types.
+func options(p libapi.Parameter) []interface{} {
+	var values []interface{}
+	for _, o := range p.Constraints.Options {
+		switch o.Value.(type) {
+		case string, bool, int, int64, float64:
+			values = append(values, o.Value)
+		default:
+			return nil
+		}
+	}
+	return values
+}
+
+// description returns the description of a parameter on a single line.
+func description(p libapi.Parameter) string {
+	return oneLine(p.Desc)
+}
+
+// oneLine collapses the whitespace in s, including newlines, into single spaces.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
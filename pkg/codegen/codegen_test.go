@@ -0,0 +1,199 @@
+package codegen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	libapi "github.com/airplanedev/lib/pkg/api"
+	"github.com/stretchr/testify/require"
+)
+
+var testTasks = []Task{
+	{
+		Slug: "my_task",
+		Name: "My task",
+		Parameters: libapi.Parameters{
+			{Slug: "name", Type: libapi.TypeString, Desc: "The name\nto greet."},
+			{Slug: "count", Type: libapi.TypeInteger, Constraints: libapi.Constraints{Optional: true}},
+			{Slug: "color", Type: libapi.TypeString, Constraints: libapi.Constraints{
+				Options: []libapi.ConstraintOption{{Label: "Red", Value: "red"}, {Label: "Blue", Value: "blue"}},
+			}},
+			{Slug: "file", Type: libapi.TypeUpload, Constraints: libapi.Constraints{Optional: true}},
+		},
+	},
+	{
+		Slug: "no_params",
+		Name: "No params",
+	},
+}
+
+func TestGenerateTypeScript(t *testing.T) {
+	require := require.New(t)
+
+	buf, err := Generate(LanguageTypeScript, testTasks)
+	require.NoError(err)
+	require.Equal(`// Code generated by airplane tasks codegen. DO NOT EDIT.
+
+import airplane from "airplane";
+
+export type Upload = { id: string; url: string };
+
+/** Parameters of My task (my_task). */
+export interface MyTaskParams {
+  /** The name to greet. */
+  name: string;
+  count?: number;
+  color: "red" | "blue";
+  file?: Upload;
+}
+
+/** Executes My task (my_task). */
+export const executeMyTask = (params: MyTaskParams) =>
+  airplane.execute("my_task", params);
+
+/** Parameters of No params (no_params). */
+export type NoParamsParams = Record<string, never>;
+
+/** Executes No params (no_params). */
+export const executeNoParams = (params: NoParamsParams) =>
+  airplane.execute("no_params", params);
+`, string(buf))
+}
+
+func TestGeneratePython(t *testing.T) {
+	require := require.New(t)
+
+	buf, err := Generate(LanguagePython, testTasks)
+	require.NoError(err)
+	require.Equal(`# Code generated by airplane tasks codegen. DO NOT EDIT.
+
+from typing import Any, Literal, TypedDict
+
+import airplane
+
+
+class Upload(TypedDict):
+    id: str
+    url: str
+
+
+class _MyTaskParamsRequired(TypedDict):
+    # The name to greet.
+    name: str
+    color: Literal["red", "blue"]
+
+
+class MyTaskParams(_MyTaskParamsRequired, total=False):
+    "Parameters of My task (my_task)."
+    count: int
+    file: Upload
+
+
+def execute_my_task(params: MyTaskParams) -> Any:
+    "Executes My task (my_task)."
+    return airplane.execute("my_task", params)
+
+
+class NoParamsParams(TypedDict):
+    "Parameters of No params (no_params)."
+
+
+def execute_no_params(params: NoParamsParams) -> Any:
+    "Executes No params (no_params)."
+    return airplane.execute("no_params", params)
+`, string(buf))
+}
+
+func TestGeneratePythonInvalidKeys(t *testing.T) {
+	require := require.New(t)
+
+	buf, err := Generate(LanguagePython, []Task{{
+		Slug: "keywords",
+		Name: "Keywords",
+		Parameters: libapi.Parameters{
+			{Slug: "from", Type: libapi.TypeDate},
+			{Slug: "to", Type: libapi.TypeDate, Constraints: libapi.Constraints{Optional: true}},
+		},
+	}})
+	require.NoError(err)
+	require.Contains(string(buf), `# Parameters of Keywords (keywords).
+KeywordsParams = TypedDict(
+    "KeywordsParams",
+    {
+        "from": str,
+        "to": str,
+    },
+    total=False,
+)
+`)
+}
+
+func TestGenerateEscapesComments(t *testing.T) {
+	require := require.New(t)
+
+	tasks := []Task{{
+		Slug: "tricky",
+		Name: "Ends */ comments\nand lines",
+		Parameters: libapi.Parameters{
+			{Slug: "name", Type: libapi.TypeString, Desc: "Not */ closed"},
+		},
+	}}
+	buf, err := Generate(LanguageTypeScript, tasks)
+	require.NoError(err)
+	require.Contains(string(buf), "/** Parameters of Ends * / comments and lines (tricky). */\n")
+	require.Contains(string(buf), "  /** Not * / closed */\n")
+	require.Contains(string(buf), "/** Executes Ends * / comments and lines (tricky). */\n")
+
+	buf, err = Generate(LanguagePython, tasks)
+	require.NoError(err)
+	require.Contains(string(buf), `"Parameters of Ends */ comments and lines (tricky)."`)
+	require.Contains(string(buf), `"Executes Ends */ comments and lines (tricky)."`)
+}
+
+func TestRegenerate(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+
+	// Only files that were generated are regenerated.
+	require.NoError(os.WriteFile(filepath.Join(dir, TypeScriptFile), []byte("// Code generated by airplane tasks codegen. DO NOT EDIT.\n"), 0644))
+	require.NoError(os.WriteFile(filepath.Join(dir, PythonFile), []byte("print('hello')\n"), 0644))
+
+	paths, err := Regenerate(dir, testTasks)
+	require.NoError(err)
+	require.Equal([]string{filepath.Join(dir, TypeScriptFile)}, paths)
+
+	buf, err := os.ReadFile(filepath.Join(dir, TypeScriptFile))
+	require.NoError(err)
+	require.Contains(string(buf), "executeMyTask")
+	buf, err = os.ReadFile(filepath.Join(dir, PythonFile))
+	require.NoError(err)
+	require.Equal("print('hello')\n", string(buf))
+}
+
+func TestFindGeneratedDir(t *testing.T) {
+	require := require.New(t)
+	root := t.TempDir()
+	sub := filepath.Join(root, "tasks", "billing")
+	require.NoError(os.MkdirAll(sub, 0755))
+
+	_, ok := FindGeneratedDir(sub)
+	require.False(ok)
+
+	// Files with a generated name that were not generated are ignored.
+	require.NoError(os.WriteFile(filepath.Join(root, "tasks", PythonFile), []byte("print('hello')\n"), 0644))
+	require.NoError(os.WriteFile(filepath.Join(root, TypeScriptFile), []byte("// Code generated by airplane tasks codegen. DO NOT EDIT.\n"), 0644))
+	dir, ok := FindGeneratedDir(sub)
+	require.True(ok)
+	require.Equal(root, dir)
+}
+
+func TestIdentifiers(t *testing.T) {
+	require := require.New(t)
+
+	require.Equal("MyTask", pascalCase("my_task"))
+	require.Equal("MyTask2", pascalCase("my-task-2"))
+	require.Equal("Task2Fast", pascalCase("2_fast"))
+	require.Equal("my_task", snakeCase("my-task"))
+	require.Equal("task_2_fast", snakeCase("2_fast"))
+}
@@ -0,0 +1,158 @@
+package codegen
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	libapi "github.com/airplanedev/lib/pkg/api"
+)
+
+var pyIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+var pyKeywords = map[string]bool{
+	"False": true, "None": true, "True": true, "and": true, "as": true, "assert": true,
+	"async": true, "await": true, "break": true, "class": true, "continue": true, "def": true,
+	"del": true, "elif": true, "else": true, "except": true, "finally": true, "for": true,
+	"from": true, "global": true, "if": true, "import": true, "in": true, "is": true,
+	"lambda": true, "nonlocal": true, "not": true, "or": true, "pass": true, "raise": true,
+	"return": true, "try": true, "while": true, "with": true, "yield": true,
+}
+
+type pyField struct {
+	key  string
+	typ  string
+	desc string
+}
+
+func generatePython(tasks []Task) []byte {
+	var body strings.Builder
+	usesLiteral := false
+	if usesUploads(tasks) {
+		body.WriteString("\n\nclass Upload(TypedDict):\n    id: str\n    url: str\n")
+	}
+
+	for _, t := range tasks {
+		name := pascalCase(t.Slug) + "Params"
+		var required, optional []pyField
+		for _, p := range t.Parameters {
+			typ := pyType(p)
+			if strings.HasPrefix(typ, "Literal[") {
+				usesLiteral = true
+			}
+			f := pyField{key: p.Slug, typ: typ, desc: description(p)}
+			if p.Constraints.Optional {
+				optional = append(optional, f)
+			} else {
+				required = append(required, f)
+			}
+		}
+
+		// TypedDicts cannot mix required and optional keys before Python 3.11, so required keys
+		// are declared in a base class.
+		doc := oneLine(fmt.Sprintf("Parameters of %s (%s).", t.Name, t.Slug))
+		switch {
+		case len(required) > 0 && len(optional) > 0 && (hasInvalidKeys(required) || hasInvalidKeys(optional)):
+			// The functional syntax does not support inheritance, so every key is optional.
+			writeTypedDict(&body, name, "TypedDict", doc, append(required, optional...), false)
+		case len(optional) == 0:
+			writeTypedDict(&body, name, "TypedDict", doc, required, true)
+		case len(required) == 0:
+			writeTypedDict(&body, name, "TypedDict", doc, optional, false)
+		default:
+			base := "_" + name + "Required"
+			writeTypedDict(&body, base, "TypedDict", "", required, true)
+			writeTypedDict(&body, name, base, doc, optional, false)
+		}
+
+		fmt.Fprintf(&body, "\n\ndef execute_%s(params: %s) -> Any:\n", snakeCase(t.Slug), name)
+		fmt.Fprintf(&body, "    %q\n", oneLine(fmt.Sprintf("Executes %s (%s).", t.Name, t.Slug)))
+		fmt.Fprintf(&body, "    return airplane.execute(%s, params)\n", strconv.Quote(t.Slug))
+	}
+
+	imports := []string{"Any", "TypedDict"}
+	if usesLiteral {
+		imports = []string{"Any", "Literal", "TypedDict"}
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", header)
+	fmt.Fprintf(&b, "from typing import %s\n\n", strings.Join(imports, ", "))
+	b.WriteString("import airplane\n")
+	b.WriteString(body.String())
+	return []byte(b.String())
+}
+
+// writeTypedDict writes a TypedDict with the class syntax, or with the functional syntax if any
+// key is not a valid identifier.
+func writeTypedDict(b *strings.Builder, name, base, doc string, fields []pyField, total bool) {
+	functional := hasInvalidKeys(fields)
+	totalArg := ""
+	if !total {
+		totalArg = ", total=False"
+	}
+
+	b.WriteString("\n\n")
+	if functional {
+		if doc != "" {
+			fmt.Fprintf(b, "# %s\n", doc)
+		}
+		fmt.Fprintf(b, "%s = TypedDict(\n    %q,\n    {\n", name, name)
+		for _, f := range fields {
+			fmt.Fprintf(b, "        %s: %s,\n", strconv.Quote(f.key), f.typ)
+		}
+		b.WriteString("    },\n")
+		if !total {
+			b.WriteString("    total=False,\n")
+		}
+		b.WriteString(")\n")
+		return
+	}
+
+	fmt.Fprintf(b, "class %s(%s%s):\n", name, base, totalArg)
+	if doc != "" {
+		fmt.Fprintf(b, "    %q\n", doc)
+	}
+	if len(fields) == 0 && doc == "" {
+		b.WriteString("    pass\n")
+	}
+	for _, f := range fields {
+		if f.desc != "" {
+			fmt.Fprintf(b, "    # %s\n", f.desc)
+		}
+		fmt.Fprintf(b, "    %s: %s\n", f.key, f.typ)
+	}
+}
+
+// hasInvalidKeys returns true if any key cannot be declared with the class syntax.
+func hasInvalidKeys(fields []pyField) bool {
+	for _, f := range fields {
+		if !pyIdentifier.MatchString(f.key) || pyKeywords[f.key] {
+			return true
+		}
+	}
+	return false
+}
+
+func pyType(p libapi.Parameter) string {
+	if values := options(p); len(values) > 0 {
+		literals := make([]string, len(values))
+		for i, v := range values {
+			literals[i] = literal(v, "True", "False")
+		}
+		return "Literal[" + strings.Join(literals, ", ") + "]"
+	}
+	switch p.Type {
+	case libapi.TypeBoolean:
+		return "bool"
+	case libapi.TypeInteger:
+		return "int"
+	case libapi.TypeFloat:
+		return "float"
+	case libapi.TypeUpload:
+		return "Upload"
+	default:
+		// Strings, dates and datetimes, and the names of configs.
+		return "str"
+	}
+}
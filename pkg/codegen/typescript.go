@@ -0,0 +1,107 @@
+package codegen
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	libapi "github.com/airplanedev/lib/pkg/api"
+)
+
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+func generateTypeScript(tasks []Task) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %s\n\n", header)
+	b.WriteString("import airplane from \"airplane\";\n")
+	if usesUploads(tasks) {
+		b.WriteString("\nexport type Upload = { id: string; url: string };\n")
+	}
+
+	for _, t := range tasks {
+		name := pascalCase(t.Slug)
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "/** %s */\n", tsComment(fmt.Sprintf("Parameters of %s (%s).", t.Name, t.Slug)))
+		if len(t.Parameters) == 0 {
+			fmt.Fprintf(&b, "export type %sParams = Record<string, never>;\n", name)
+		} else {
+			fmt.Fprintf(&b, "export interface %sParams {\n", name)
+			for _, p := range t.Parameters {
+				if desc := description(p); desc != "" {
+					fmt.Fprintf(&b, "  /** %s */\n", tsComment(desc))
+				}
+				key := p.Slug
+				if !tsIdentifier.MatchString(key) {
+					key = strconv.Quote(key)
+				}
+				optional := ""
+				if p.Constraints.Optional {
+					optional = "?"
+				}
+				fmt.Fprintf(&b, "  %s%s: %s;\n", key, optional, tsType(p))
+			}
+			b.WriteString("}\n")
+		}
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "/** %s */\n", tsComment(fmt.Sprintf("Executes %s (%s).", t.Name, t.Slug)))
+		fmt.Fprintf(&b, "export const execute%s = (params: %sParams) =>\n", name, name)
+		fmt.Fprintf(&b, "  airplane.execute(%s, params);\n", strconv.Quote(t.Slug))
+	}
+	return []byte(b.String())
+}
+
+// tsComment returns s on a single line that cannot end the doc comment it is written in.
+func tsComment(s string) string {
+	return strings.ReplaceAll(oneLine(s), "*/", "* /")
+}
+
+func tsType(p libapi.Parameter) string {
+	if values := options(p); len(values) > 0 {
+		literals := make([]string, len(values))
+		for i, v := range values {
+			literals[i] = literal(v, "true", "false")
+		}
+		return strings.Join(literals, " | ")
+	}
+	switch p.Type {
+	case libapi.TypeBoolean:
+		return "boolean"
+	case libapi.TypeInteger, libapi.TypeFloat:
+		return "number"
+	case libapi.TypeUpload:
+		return "Upload"
+	default:
+		// Strings, dates and datetimes, and the names of configs.
+		return "string"
+	}
+}
+
+func usesUploads(tasks []Task) bool {
+	for _, t := range tasks {
+		for _, p := range t.Parameters {
+			if p.Type == libapi.TypeUpload {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// literal formats an option value as a literal in TypeScript or Python, which only differ in
+// how booleans are spelled.
+func literal(v interface{}, trueLiteral, falseLiteral string) string {
+	switch v := v.(type) {
+	case string:
+		return strconv.Quote(v)
+	case bool:
+		if v {
+			return trueLiteral
+		}
+		return falseLiteral
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,78 @@
+package conf
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ArchiveCache maps the content hashes of build roots to the uploads that contain them, so
+// that unchanged build roots are not uploaded again.
+type ArchiveCache struct {
+	Uploads map[string]CachedUpload `json:"uploads,omitempty"`
+}
+
+// CachedUpload is a build archive that was previously uploaded.
+type CachedUpload struct {
+	UploadID  string    `json:"uploadID"`
+	SizeBytes int       `json:"sizeBytes"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// DefaultArchiveCachePath returns the default location of the archive cache.
+func DefaultArchiveCachePath() string {
+	homedir, err := os.UserHomeDir()
+	if err != nil {
+		// TODO(amir): friendly output.
+		panic("$HOME environment variable must be set")
+	}
+	return filepath.Join(
+		homedir,
+		".airplane",
+		"archives.json",
+	)
+}
+
+// ReadArchiveCache reads the archive cache from the given path. If the cache does not exist,
+// an empty cache is returned.
+func ReadArchiveCache(path string) (ArchiveCache, error) {
+	cache := ArchiveCache{Uploads: map[string]CachedUpload{}}
+
+	buf, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cache, nil
+	} else if err != nil {
+		return cache, errors.Wrap(err, "read archive cache")
+	}
+
+	if err := json.Unmarshal(buf, &cache); err != nil {
+		return cache, errors.Wrap(err, "unmarshal archive cache")
+	}
+	if cache.Uploads == nil {
+		cache.Uploads = map[string]CachedUpload{}
+	}
+
+	return cache, nil
+}
+
+// WriteArchiveCache writes the archive cache to the given path.
+func WriteArchiveCache(path string, cache ArchiveCache) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return errors.Wrap(err, "mkdir")
+	}
+
+	buf, err := json.MarshalIndent(cache, "", "	")
+	if err != nil {
+		return errors.Wrap(err, "marshal archive cache")
+	}
+
+	if err := ioutil.WriteFile(path, buf, 0600); err != nil {
+		return errors.Wrap(err, "write archive cache")
+	}
+
+	return nil
+}
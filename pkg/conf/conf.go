@@ -2,6 +2,7 @@ package conf
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -45,6 +46,32 @@ func GetGitUser() string {
 	return os.Getenv("AP_GIT_USER")
 }
 
+// GetGitVendors gets a mapping of git hosts to vendors from an env var, if one exists. The env
+// var is a comma-separated list of <host>=<vendor> pairs, e.g. git.example.com=GitLab.
+func GetGitVendors() map[string]string {
+	vendors := map[string]string{}
+	for _, pair := range strings.Split(os.Getenv("AP_GIT_VENDORS"), ",") {
+		host, vendor, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if ok && host != "" && vendor != "" {
+			vendors[strings.TrimSpace(host)] = strings.TrimSpace(vendor)
+		}
+	}
+	return vendors
+}
+
+// GetTemplatesDir gets the directory of local templates from an env var, or defaults to
+// ~/.airplane/templates.
+func GetTemplatesDir() string {
+	if dir := os.Getenv("AP_TEMPLATES_DIR"); dir != "" {
+		return dir
+	}
+	homedir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homedir, ".airplane", "templates")
+}
+
 // GetSource gets the source from an env var, if it exists.
 func GetSource() string {
 	return os.Getenv("AP_SOURCE")
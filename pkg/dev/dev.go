@@ -64,6 +64,8 @@ type LocalRunConfig struct {
 	Resources map[string]resources.Resource
 	IsBuiltin bool
 	LogBroker logs.LogBroker
+	// NoTruncate prints the outputs of the run in full.
+	NoTruncate bool
 }
 
 type CmdConfig struct {
@@ -129,7 +131,30 @@ func (l *LocalExecutor) Cmd(ctx context.Context, config LocalRunConfig) (CmdConf
 	}, nil
 }
 
-func (l *LocalExecutor) Execute(ctx context.Context, config LocalRunConfig) (api.Outputs, error) {
+// NotStartedError is returned by Execute if the task failed before it started running, in which
+// case there are no outputs.
+type NotStartedError struct {
+	Err error
+}
+
+// Error implementation.
+func (err NotStartedError) Error() string {
+	return err.Err.Error()
+}
+
+// Unwrap implementation.
+func (err NotStartedError) Unwrap() error {
+	return err.Err
+}
+
+func (l *LocalExecutor) Execute(ctx context.Context, config LocalRunConfig) (_ api.Outputs, rerr error) {
+	started := false
+	defer func() {
+		if rerr != nil && !started {
+			rerr = NotStartedError{Err: rerr}
+		}
+	}()
+
 	cmdConfig, err := l.Cmd(ctx, config)
 	if cmdConfig.closer != nil {
 		defer cmdConfig.closer.Close()
@@ -178,6 +203,7 @@ func (l *LocalExecutor) Execute(ctx context.Context, config LocalRunConfig) (api
 	if err := cmd.Start(); err != nil {
 		return api.Outputs{}, errors.Wrap(err, "starting")
 	}
+	started = true
 
 	if config.LogBroker == nil {
 		config.LogBroker = &logs.MockLogBroker{}
@@ -223,7 +249,7 @@ func (l *LocalExecutor) Execute(ctx context.Context, config LocalRunConfig) (api
 	outputs := api.Outputs(o)
 	logger.Log("")
 	logger.Log("%s for task %s:", logger.Gray("Output"), logger.Gray(config.Slug))
-	print.Outputs(outputs)
+	print.Outputs(outputs, print.NewTruncation(config.NoTruncate))
 
 	logger.Log("")
 	print.BoxPrint(fmt.Sprintf("Finished running task [%s]", config.Slug))
@@ -0,0 +1,86 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFile is the name of the repo-level policy config. It is looked up in the directory
+// that is being deployed or linted and its parents.
+const ConfigFile = "airplane.policy.yaml"
+
+// Config enables and disables rules and sets their severities.
+type Config struct {
+	// Path is the file that the config was read from, or empty if there is none.
+	Path string `yaml:"-"`
+	// Rules maps rule names to their severity. Rules that are not listed use their default
+	// severity.
+	Rules map[string]Severity `yaml:"rules"`
+	// ApprovedResources are the slugs of the resources that tasks may attach. If unset, tasks
+	// may attach any resource.
+	ApprovedResources []string `yaml:"approvedResources"`
+}
+
+// severity returns the configured severity of a rule. Without a config file, every rule is at
+// most a warning, so that deploys are only blocked once a team has opted into its policies.
+func (c Config) severity(rule Rule) Severity {
+	if s, ok := c.Rules[rule.Name]; ok {
+		return s
+	}
+	if c.Path == "" && rule.DefaultSeverity == SeverityError {
+		return SeverityWarning
+	}
+	return rule.DefaultSeverity
+}
+
+// FindConfig reads the policy config in dir or the closest of its parents. If there is no
+// config, the default config is returned.
+func FindConfig(dir string) (Config, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return Config{}, err
+	}
+	for {
+		path := filepath.Join(dir, ConfigFile)
+		if _, err := os.Stat(path); err == nil {
+			return ReadConfig(path)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return Config{}, nil
+		}
+		dir = parent
+	}
+}
+
+// ReadConfig reads the policy config at path.
+func ReadConfig(path string) (Config, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, errors.Wrap(err, "reading policy config")
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(buf, &cfg); err != nil {
+		return Config{}, errors.Wrapf(err, "parsing %s", path)
+	}
+	cfg.Path = path
+
+	known := map[string]bool{}
+	for _, rule := range Rules {
+		known[rule.Name] = true
+	}
+	for name, severity := range cfg.Rules {
+		if !known[name] {
+			return Config{}, errors.Errorf("%s: unknown rule %q", path, name)
+		}
+		switch severity {
+		case SeverityOff, SeverityWarning, SeverityError:
+		default:
+			return Config{}, errors.Errorf("%s: invalid severity %q for rule %s: expected off, warning or error", path, severity, name)
+		}
+	}
+	return cfg, nil
+}
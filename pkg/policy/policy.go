@@ -0,0 +1,170 @@
+// Package policy checks task definitions against a team's conventions before they are
+// deployed.
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/airplanedev/lib/pkg/deploy/taskdir/definitions"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+type Severity string
+
+const (
+	SeverityOff     Severity = "off"
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+// Finding is a violation of a rule by a task definition.
+type Finding struct {
+	Rule     string   `json:"rule" yaml:"rule"`
+	Severity Severity `json:"severity" yaml:"severity"`
+	Slug     string   `json:"slug" yaml:"slug"`
+	// File is the definition file of the task, if any.
+	File string `json:"file,omitempty" yaml:"file,omitempty"`
+	// Line is the 1-indexed line of File that the finding refers to, or 0 if unknown.
+	Line    int    `json:"line,omitempty" yaml:"line,omitempty"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// Task is the view of a task definition that rules check.
+type Task struct {
+	Slug           string
+	Kind           string
+	DefinitionFile string
+	// Definition is the YAML definition of the task, decoded into maps and slices.
+	Definition map[string]interface{}
+	Env        map[string]EnvVar
+	Resources  []string
+
+	// lines are the lines of the top-level fields in DefinitionFile.
+	lines map[string]int
+}
+
+// EnvVar is an environment variable of a task, set either to a value or to a config.
+type EnvVar struct {
+	Value  *string
+	Config *string
+}
+
+// line returns the line of a top-level field of the definition file, or 0 if unknown.
+func (t Task) line(field string) int {
+	return t.lines[field]
+}
+
+// NewTask returns the view of def that rules check.
+func NewTask(def definitions.DefinitionInterface) (Task, error) {
+	kind, _, err := def.GetKindAndOptions()
+	if err != nil {
+		return Task{}, err
+	}
+	buf, err := def.Marshal(definitions.DefFormatYAML)
+	if err != nil {
+		return Task{}, errors.Wrap(err, "marshalling task definition")
+	}
+	var decoded map[string]interface{}
+	if err := yaml.Unmarshal(buf, &decoded); err != nil {
+		return Task{}, errors.Wrap(err, "parsing task definition")
+	}
+
+	env, err := def.GetEnv()
+	if err != nil {
+		return Task{}, err
+	}
+	t := Task{
+		Slug:           def.GetSlug(),
+		Kind:           string(kind),
+		DefinitionFile: def.GetDefnFilePath(),
+		Definition:     decoded,
+		Env:            map[string]EnvVar{},
+		Resources:      ResourceDependencies(def),
+	}
+	for name, v := range env {
+		t.Env[name] = EnvVar{Value: v.Value, Config: v.Config}
+	}
+	t.lines = readFieldLines(t.DefinitionFile)
+	return t, nil
+}
+
+// Check returns the findings of the enabled rules for a task, sorted by severity.
+func Check(cfg Config, t Task) []Finding {
+	var findings []Finding
+	for _, rule := range Rules {
+		severity := cfg.severity(rule)
+		if severity == SeverityOff {
+			continue
+		}
+		for _, v := range rule.Check(cfg, t) {
+			findings = append(findings, Finding{
+				Rule:     rule.Name,
+				Severity: severity,
+				Slug:     t.Slug,
+				File:     t.DefinitionFile,
+				Line:     v.line,
+				Message:  v.message,
+			})
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Severity == SeverityError && findings[j].Severity != SeverityError
+	})
+	return findings
+}
+
+// HasErrors returns true if any of the findings has error severity.
+func HasErrors(findings []Finding) bool {
+	for _, f := range findings {
+		if f.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// ResourceDependencies returns the sorted slugs of the resources that a task attaches, either
+// under `resources` or as the resource of a SQL or REST task.
+func ResourceDependencies(def definitions.DefinitionInterface) []string {
+	seen := map[string]bool{}
+	var slugs []string
+	for _, slug := range def.GetResourceAttachments() {
+		if slug != "" && !seen[slug] {
+			seen[slug] = true
+			slugs = append(slugs, slug)
+		}
+	}
+	sort.Strings(slugs)
+	return slugs
+}
+
+// readFieldLines returns the lines of the top-level fields of a YAML or JSON definition file.
+func readFieldLines(file string) map[string]int {
+	lines := map[string]int{}
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml", ".json":
+	default:
+		return lines
+	}
+	buf, err := os.ReadFile(file)
+	if err != nil {
+		return lines
+	}
+	// JSON is valid YAML.
+	var doc yaml.Node
+	if err := yaml.Unmarshal(buf, &doc); err != nil || len(doc.Content) == 0 {
+		return lines
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return lines
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		lines[root.Content[i].Value] = root.Content[i].Line
+	}
+	return lines
+}
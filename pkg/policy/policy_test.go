@@ -0,0 +1,119 @@
+package policy
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/ci"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	require := require.New(t)
+
+	value := "sk_test_123"
+	config := "stripe_key"
+	task := Task{
+		Slug: "my_task",
+		Kind: "node",
+		Definition: map[string]interface{}{
+			"slug":    "my_task",
+			"timeout": 60,
+		},
+		Env: map[string]EnvVar{
+			"STRIPE_API_KEY": {Value: &value},
+			"OTHER_API_KEY":  {Config: &config},
+			"LOG_LEVEL":      {Value: &value},
+		},
+		Resources: []string{"db", "prod_db"},
+		lines:     map[string]int{"slug": 1, "node": 5},
+	}
+
+	// Without a config file, rules are at most warnings.
+	findings := Check(Config{}, task)
+	require.Len(findings, 2)
+	require.False(HasErrors(findings))
+
+	findings = Check(Config{Path: ConfigFile}, task)
+	require.Equal([]Finding{
+		{
+			Rule:     "no-plaintext-secrets",
+			Severity: SeverityError,
+			Slug:     "my_task",
+			Line:     5,
+			Message:  "Environment variable STRIPE_API_KEY of task my_task looks like a secret but is set to a plaintext value. Set it from a config instead.",
+		},
+		{
+			Rule:     "description-required",
+			Severity: SeverityWarning,
+			Slug:     "my_task",
+			Line:     1,
+			Message:  "Task my_task has no description.",
+		},
+	}, findings)
+	require.True(HasErrors(findings))
+
+	findings = Check(Config{
+		Path: ConfigFile,
+		Rules: map[string]Severity{
+			"description-required": SeverityError,
+			"no-plaintext-secrets": SeverityOff,
+		},
+		ApprovedResources: []string{"db"},
+	}, task)
+	require.Len(findings, 2)
+	require.Equal("description-required", findings[0].Rule)
+	require.Equal(SeverityError, findings[0].Severity)
+	require.Equal("approved-resources", findings[1].Rule)
+	require.Contains(findings[1].Message, "prod_db")
+}
+
+func TestReadConfig(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, ConfigFile)
+	require.NoError(os.WriteFile(path, []byte("rules:\n  timeout-required: off\napprovedResources: [db]\n"), 0644))
+	require.NoError(os.MkdirAll(filepath.Join(dir, "tasks", "a"), 0755))
+
+	cfg, err := FindConfig(filepath.Join(dir, "tasks", "a"))
+	require.NoError(err)
+	require.Equal(path, cfg.Path)
+	require.Equal(map[string]Severity{"timeout-required": SeverityOff}, cfg.Rules)
+	require.Equal([]string{"db"}, cfg.ApprovedResources)
+
+	require.NoError(os.WriteFile(path, []byte("rules:\n  timeout-required: fatal\n"), 0644))
+	_, err = ReadConfig(path)
+	require.ErrorContains(err, "invalid severity")
+
+	require.NoError(os.WriteFile(path, []byte("rules:\n  no-such-rule: error\n"), 0644))
+	_, err = ReadConfig(path)
+	require.ErrorContains(err, "unknown rule")
+}
+
+func TestAnnotations(t *testing.T) {
+	require := require.New(t)
+
+	var b bytes.Buffer
+	require.NoError(ci.WriteGitHubAnnotations(&b, Annotations([]Finding{
+		{Rule: "description-required", Severity: SeverityWarning, File: "/repo/a,b.task.yaml", Line: 2, Message: "No description:\n100%"},
+		{Rule: "no-plaintext-secrets", Severity: SeverityError, Message: "Secret"},
+	})))
+	require.Equal("::warning title=description-required,file=/repo/a%2Cb.task.yaml,line=2::No description:%0A100%25\n"+
+		"::error title=no-plaintext-secrets::Secret\n", b.String())
+}
+
+func TestReadFieldLines(t *testing.T) {
+	require := require.New(t)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "my_task.task.yaml")
+	require.NoError(os.WriteFile(path, []byte("slug: my_task\nname: My task\n\nnode:\n  entrypoint: my_task.ts\n"), 0644))
+	require.Equal(map[string]int{"slug": 1, "name": 2, "node": 4}, readFieldLines(path))
+
+	path = filepath.Join(dir, "my_task.task.json")
+	require.NoError(os.WriteFile(path, []byte("{\n  \"slug\": \"my_task\",\n  \"name\": \"My task\"\n}\n"), 0644))
+	require.Equal(map[string]int{"slug": 2, "name": 3}, readFieldLines(path))
+}
@@ -0,0 +1,50 @@
+package policy
+
+import (
+	"fmt"
+
+	"github.com/airplanedev/cli/pkg/ci"
+	"github.com/airplanedev/cli/pkg/logger"
+)
+
+// Log logs findings in the format `file:line: severity: message [rule]`, which editors and
+// most CI systems recognize.
+func Log(l logger.Logger, findings []Finding) {
+	for _, f := range findings {
+		severity := logger.Yellow("%s", f.Severity)
+		if f.Severity == SeverityError {
+			severity = logger.Red("%s", f.Severity)
+		}
+		l.Log("%s%s: %s %s", location(f), severity, f.Message, logger.Gray("[%s]", f.Rule))
+	}
+}
+
+func location(f Finding) string {
+	if f.File == "" {
+		return ""
+	}
+	file := ci.RelativePath(f.File)
+	if f.Line > 0 {
+		return fmt.Sprintf("%s:%d: ", file, f.Line)
+	}
+	return file + ": "
+}
+
+// Annotations returns findings as annotations, to be written with ci.WriteGitHubAnnotations.
+func Annotations(findings []Finding) []ci.Annotation {
+	annotations := make([]ci.Annotation, len(findings))
+	for i, f := range findings {
+		level := ci.AnnotationWarning
+		if f.Severity == SeverityError {
+			level = ci.AnnotationError
+		}
+		annotations[i] = ci.Annotation{
+			Level:   level,
+			Title:   f.Rule,
+			File:    f.File,
+			Line:    f.Line,
+			Message: f.Message,
+		}
+	}
+	return annotations
+}
@@ -0,0 +1,131 @@
+package policy
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Rule is a built-in policy rule.
+type Rule struct {
+	Name        string
+	Description string
+	// DefaultSeverity is the severity of the rule if a config file does not configure it.
+	// Without a config file, rules are at most warnings.
+	DefaultSeverity Severity
+	Check           func(cfg Config, t Task) []violation
+}
+
+type violation struct {
+	line    int
+	message string
+}
+
+// Rules are the built-in rules, in the order they are checked.
+var Rules = []Rule{
+	{
+		Name:            "description-required",
+		Description:     "Tasks must have a description.",
+		DefaultSeverity: SeverityWarning,
+		Check:           checkDescription,
+	},
+	{
+		Name:            "timeout-required",
+		Description:     "Tasks must set a timeout.",
+		DefaultSeverity: SeverityWarning,
+		Check:           checkTimeout,
+	},
+	{
+		Name:            "no-plaintext-secrets",
+		Description:     "Environment variables that look like secrets must be set from configs.",
+		DefaultSeverity: SeverityError,
+		Check:           checkPlaintextSecrets,
+	},
+	{
+		Name:            "approved-resources",
+		Description:     "Tasks may only attach resources listed under approvedResources.",
+		DefaultSeverity: SeverityError,
+		Check:           checkApprovedResources,
+	},
+}
+
+func checkDescription(cfg Config, t Task) []violation {
+	if d, _ := t.Definition["description"].(string); strings.TrimSpace(d) != "" {
+		return nil
+	}
+	return []violation{{
+		line:    t.line("slug"),
+		message: fmt.Sprintf("Task %s has no description.", t.Slug),
+	}}
+}
+
+func checkTimeout(cfg Config, t Task) []violation {
+	switch timeout := t.Definition["timeout"].(type) {
+	case int:
+		if timeout > 0 {
+			return nil
+		}
+	case float64:
+		if timeout > 0 {
+			return nil
+		}
+	}
+	return []violation{{
+		line:    t.line("slug"),
+		message: fmt.Sprintf("Task %s does not set a timeout.", t.Slug),
+	}}
+}
+
+var secretEnvName = regexp.MustCompile(`(?i)(secret|token|passw(or)?d|api_?key|access_?key|private_?key|credential)`)
+
+func checkPlaintextSecrets(cfg Config, t Task) []violation {
+	var names []string
+	for name, v := range t.Env {
+		if v.Value == nil || v.Config != nil || !secretEnvName.MatchString(name) {
+			continue
+		}
+		value := strings.TrimSpace(*v.Value)
+		// Values that are interpolated, e.g. from a config, are not plaintext.
+		if value == "" || strings.Contains(value, "{{") {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var violations []violation
+	for _, name := range names {
+		violations = append(violations, violation{
+			line:    t.line(t.Kind),
+			message: fmt.Sprintf("Environment variable %s of task %s looks like a secret but is set to a plaintext value. Set it from a config instead.", name, t.Slug),
+		})
+	}
+	return violations
+}
+
+func checkApprovedResources(cfg Config, t Task) []violation {
+	// The rule only applies once a team has listed its approved resources.
+	if cfg.ApprovedResources == nil {
+		return nil
+	}
+	approved := map[string]bool{}
+	for _, slug := range cfg.ApprovedResources {
+		approved[slug] = true
+	}
+
+	line := t.line("resources")
+	if line == 0 {
+		line = t.line(t.Kind)
+	}
+	var violations []violation
+	for _, slug := range t.Resources {
+		if !approved[slug] {
+			violations = append(violations, violation{
+				line:    line,
+				message: fmt.Sprintf("Task %s attaches resource %s, which is not an approved resource.", t.Slug, slug),
+			})
+		}
+	}
+	return violations
+}
@@ -0,0 +1,27 @@
+package print //nolint: predeclared
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/airplanedev/cli/pkg/api"
+)
+
+// NewCSVFormatter returns a formatter that prints values as CSV with a header row. Nested
+// values are encoded as JSON. If columns is empty, a column is included for every field.
+func NewCSVFormatter(columns []string) Formatter {
+	return itemFormatter{
+		write: func(v interface{}) {
+			handleErr(writeCSVTable(os.Stdout, recordsTable(toItems(v), columns)))
+		},
+		writeOutputs: func(outputs api.Outputs) {
+			// Each named output is printed as its own table, separated by a blank line.
+			for i, t := range outputsToTables(outputs) {
+				if i > 0 {
+					fmt.Fprintln(os.Stdout)
+				}
+				handleErr(writeCSVTable(os.Stdout, t))
+			}
+		},
+	}
+}
@@ -0,0 +1,206 @@
+package print //nolint: predeclared
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/airplanedev/cli/pkg/logger"
+	libapi "github.com/airplanedev/lib/pkg/api"
+	"github.com/airplanedev/ojson"
+	"github.com/olekukonko/tablewriter"
+)
+
+// Truncation limits how much of large outputs and displays is rendered.
+type Truncation struct {
+	// MaxRows is the maximum number of rows that are rendered in a table before the remaining
+	// rows are elided. If zero, all rows are rendered.
+	MaxRows int
+	// MaxValueLength is the maximum number of characters of a single value that are rendered
+	// before it is truncated. If zero, values are never truncated.
+	MaxValueLength int
+}
+
+// NewTruncation returns the default truncation, or no truncation if noTruncate is set.
+func NewTruncation(noTruncate bool) Truncation {
+	if noTruncate {
+		return Truncation{}
+	}
+	return Truncation{MaxRows: 100, MaxValueLength: 1000}
+}
+
+// Displays prints the displays created by a run. Displays are only rendered by the table
+// formatter, since the JSON and YAML formatters are reserved for the run's outputs.
+func Displays(displays []libapi.Display, t Truncation) {
+	if _, ok := DefaultFormatter.(Table); !ok {
+		return
+	}
+	for _, display := range displays {
+		BoxPrintWithPrefix(RenderDisplay(display, t), "["+logger.Gray("display")+"] ")
+	}
+}
+
+// RenderDisplay renders a run display for the terminal.
+func RenderDisplay(display libapi.Display, t Truncation) string {
+	switch display.Kind {
+	case "markdown":
+		return RenderMarkdown(display.Content)
+	case "json":
+		buf, err := json.MarshalIndent(display.Value, "", "  ")
+		if err != nil {
+			return fmt.Sprintf("%v", display.Value)
+		}
+		return t.value(string(buf))
+	case "table":
+		return renderDisplayTable(display, t)
+	default:
+		return fmt.Sprintf("[kind=%s]\n\n%s", display.Kind, display.Content)
+	}
+}
+
+type displayColumn struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+func renderDisplayTable(display libapi.Display, t Truncation) string {
+	// Round-trip through JSON so that we only depend on the wire format of table displays. Rows
+	// are decoded as ordered objects, so that inferred columns keep the order of their keys.
+	var columns []displayColumn
+	var rows []*ojson.Object
+	if buf, err := json.Marshal(display.Columns); err == nil {
+		handleErr(json.Unmarshal(buf, &columns))
+	}
+	if buf, err := json.Marshal(display.Rows); err == nil {
+		var v ojson.Value
+		handleErr(json.Unmarshal(buf, &v))
+		rows = objects(v.V)
+	}
+
+	if len(columns) == 0 {
+		columns = inferColumns(rows)
+	}
+
+	var b bytes.Buffer
+	tw := tablewriter.NewWriter(&b)
+	tw.SetBorder(true)
+	tw.SetAutoWrapText(true)
+	tw.SetColWidth(70)
+	tw.SetAutoFormatHeaders(false)
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = c.Name
+		if header[i] == "" {
+			header[i] = c.Slug
+		}
+	}
+	tw.SetHeader(header)
+	shown, elided := t.rows(len(rows))
+	for _, row := range rows[:shown] {
+		values := make([]string, len(columns))
+		for i, c := range columns {
+			v, _ := row.Get(c.Slug)
+			values[i] = t.value(getCellValue(v))
+		}
+		tw.Append(values)
+	}
+	tw.Render()
+	if elided > 0 {
+		b.WriteString(logger.Gray("... %d more rows", elided))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// inferColumns returns a column for each key of the rows, in the order they are first seen.
+func inferColumns(rows []*ojson.Object) []displayColumn {
+	var columns []displayColumn
+	seen := map[string]bool{}
+	for _, row := range rows {
+		for _, key := range row.KeyOrder() {
+			if !seen[key] {
+				seen[key] = true
+				columns = append(columns, displayColumn{Slug: key})
+			}
+		}
+	}
+	return columns
+}
+
+// objects returns the objects in v, which is expected to be an array of objects. Other values are
+// skipped.
+func objects(v interface{}) []*ojson.Object {
+	values, _ := v.([]interface{})
+	var objects []*ojson.Object
+	for _, value := range values {
+		if o, ok := value.(*ojson.Object); ok {
+			objects = append(objects, o)
+		}
+	}
+	return objects
+}
+
+// rows returns how many of n rows should be rendered, and how many are elided.
+func (t Truncation) rows(n int) (shown, elided int) {
+	if t.MaxRows > 0 && n > t.MaxRows {
+		return t.MaxRows, n - t.MaxRows
+	}
+	return n, 0
+}
+
+// value shortens s to MaxValueLength characters.
+func (t Truncation) value(s string) string {
+	r := []rune(s)
+	if t.MaxValueLength <= 0 || len(r) <= t.MaxValueLength {
+		return s
+	}
+	return string(r[:t.MaxValueLength]) + logger.Gray("... (%d more characters)", len(r)-t.MaxValueLength)
+}
+
+var (
+	mdHeading = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	mdBullet  = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	mdBold    = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	mdCode    = regexp.MustCompile("`([^`]+)`")
+	mdLink    = regexp.MustCompile(`\[([^\]]+)\]\(([^)]+)\)`)
+)
+
+// RenderMarkdown renders a subset of markdown for the terminal: headings, lists, bold text,
+// inline code, code blocks and links.
+func RenderMarkdown(md string) string {
+	var lines []string
+	inCodeBlock := false
+	for _, line := range strings.Split(strings.TrimRight(md, "\n"), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCodeBlock = !inCodeBlock
+			continue
+		}
+		if inCodeBlock {
+			lines = append(lines, "    "+logger.Gray("%s", line))
+			continue
+		}
+
+		if m := mdHeading.FindStringSubmatch(line); m != nil {
+			lines = append(lines, logger.Bold("%s", renderInlineMarkdown(m[2])))
+			continue
+		}
+		if m := mdBullet.FindStringSubmatch(line); m != nil {
+			lines = append(lines, m[1]+"• "+renderInlineMarkdown(m[2]))
+			continue
+		}
+		lines = append(lines, renderInlineMarkdown(line))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func renderInlineMarkdown(s string) string {
+	s = mdLink.ReplaceAllString(s, "$1 ("+logger.Blue("$2")+")")
+	s = mdCode.ReplaceAllStringFunc(s, func(m string) string {
+		return logger.Blue("%s", strings.Trim(m, "`"))
+	})
+	s = mdBold.ReplaceAllStringFunc(s, func(m string) string {
+		return logger.Bold("%s", strings.Trim(m, "*_"))
+	})
+	return s
+}
@@ -0,0 +1,58 @@
+package print //nolint: predeclared
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/airplanedev/ojson"
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	color.NoColor = true
+
+	require.Equal(t, strings.Join([]string{
+		"Report",
+		"Processed 3 files with bold text.",
+		"• first item",
+		"  • nested code",
+		"    const x = 1;",
+		"See docs (https://docs.airplane.dev).",
+	}, "\n"), RenderMarkdown(strings.Join([]string{
+		"# Report",
+		"Processed 3 files with **bold** text.",
+		"- first item",
+		"  * nested `code`",
+		"```js",
+		"const x = 1;",
+		"```",
+		"See [docs](https://docs.airplane.dev).",
+	}, "\n")))
+}
+
+func TestTruncation(t *testing.T) {
+	require := require.New(t)
+	color.NoColor = true
+
+	tr := Truncation{MaxRows: 2, MaxValueLength: 5}
+	require.Equal("abcde... (2 more characters)", tr.value("abcdefg"))
+	shown, elided := tr.rows(5)
+	require.Equal(2, shown)
+	require.Equal(3, elided)
+
+	tr = NewTruncation(true)
+	require.Equal("abcdefg", tr.value("abcdefg"))
+	shown, elided = tr.rows(5)
+	require.Equal(5, shown)
+	require.Equal(0, elided)
+}
+
+func TestInferColumns(t *testing.T) {
+	rows := objects(ojson.MustNewValueFromJSON(`[
+		{"name": "alice", "id": 1},
+		{"age": 30, "name": "bob"},
+		"not an object"
+	]`).V)
+	require.Equal(t, []displayColumn{{Slug: "name"}, {Slug: "id"}, {Slug: "age"}}, inferColumns(rows))
+}
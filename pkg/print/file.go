@@ -0,0 +1,184 @@
+package print //nolint: predeclared
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/ojson"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// WriteOutputsFile writes outputs to a file. The format is chosen by the file's extension:
+// .json, .yaml/.yml or .csv.
+//
+// CSV files can only hold a single table, so if the outputs contain more than one named
+// output, a separate file is written for each, e.g. outputs.csv is written as
+// outputs.users.csv and outputs.orders.csv. If there are no outputs, the file is empty.
+func WriteOutputsFile(path string, outputs api.Outputs) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		buf, err := json.MarshalIndent(ojson.Value(outputs), "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "marshalling outputs")
+		}
+		return writeFile(path, append(buf, '\n'))
+	case ".yaml", ".yml":
+		buf, err := outputsToYAML(outputs)
+		if err != nil {
+			return err
+		}
+		return writeFile(path, buf)
+	case ".csv":
+		tables := outputsToTables(outputs)
+		switch len(tables) {
+		case 0:
+			return writeFile(path, nil)
+		case 1:
+			return writeCSV(path, tables[0])
+		}
+		ext := filepath.Ext(path)
+		written := map[string]string{}
+		for _, t := range tables {
+			name := fileNameSafe(t.name)
+			if other, ok := written[name]; ok {
+				return errors.Errorf("outputs %q and %q would both be written to %s", other, t.name, name)
+			}
+			written[name] = t.name
+			if err := writeCSV(strings.TrimSuffix(path, ext)+"."+name+ext, t); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return errors.Errorf("unsupported outputs file %s: expected a .json, .yaml, .yml or .csv extension", path)
+	}
+}
+
+// outputsToYAML converts outputs to YAML while preserving the order of object keys.
+func outputsToYAML(outputs api.Outputs) ([]byte, error) {
+	buf, err := json.Marshal(ojson.Value(outputs))
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling outputs")
+	}
+	// JSON is a subset of YAML, so decoding into a yaml.Node keeps the key order.
+	var node yaml.Node
+	if err := yaml.Unmarshal(buf, &node); err != nil {
+		return nil, errors.Wrap(err, "converting outputs to yaml")
+	}
+	resetYAMLStyle(&node)
+	out, err := yaml.Marshal(&node)
+	return out, errors.Wrap(err, "marshalling outputs to yaml")
+}
+
+// resetYAMLStyle drops the flow style that nodes pick up from being parsed as JSON.
+func resetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, n := range node.Content {
+		resetYAMLStyle(n)
+	}
+}
+
+type outputTable struct {
+	name   string
+	header []string
+	rows   [][]string
+}
+
+// outputsToTables flattens outputs into tables. Arrays of objects become a table with a
+// column per key; any other value becomes a single "value" column.
+func outputsToTables(outputs api.Outputs) []outputTable {
+	if outputs.V == nil {
+		return nil
+	}
+	if o, ok := outputs.V.(*ojson.Object); ok {
+		var tables []outputTable
+		for _, key := range o.KeyOrder() {
+			v, _ := o.Get(key)
+			tables = append(tables, valueToTable(key, v))
+		}
+		return tables
+	}
+	return []outputTable{valueToTable("output", outputs.V)}
+}
+
+func valueToTable(name string, value interface{}) outputTable {
+	t := outputTable{name: name, header: []string{"value"}}
+	values, ok := value.([]interface{})
+	if !ok {
+		t.rows = [][]string{{getCellValue(value)}}
+		return t
+	}
+	isObjects, objects := parseArrayOfJsonObject(values)
+	if !isObjects || len(objects) == 0 {
+		for _, v := range values {
+			t.rows = append(t.rows, []string{getCellValue(v)})
+		}
+		return t
+	}
+
+	t.header = nil
+	seen := map[string]bool{}
+	for _, object := range objects {
+		for _, key := range object.KeyOrder() {
+			if !seen[key] {
+				t.header = append(t.header, key)
+				seen[key] = true
+			}
+		}
+	}
+	for _, object := range objects {
+		row := make([]string, len(t.header))
+		for i, key := range t.header {
+			v, _ := object.Get(key)
+			row[i] = getCellValue(v)
+		}
+		t.rows = append(t.rows, row)
+	}
+	return t
+}
+
+func writeCSV(path string, t outputTable) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.Wrapf(err, "creating %s", path)
+	}
+	if err := writeCSVTable(f, t); err != nil {
+		f.Close()
+		return errors.Wrapf(err, "writing %s", path)
+	}
+	return errors.Wrapf(f.Close(), "writing %s", path)
+}
+
+// fileNameSafe replaces the characters of an output's name that are not safe in a file name,
+// such as path separators, with underscores.
+func fileNameSafe(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+	if name == "" {
+		return "_"
+	}
+	return name
+}
+
+func writeCSVTable(w io.Writer, t outputTable) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(t.header); err != nil {
+		return err
+	}
+	return cw.WriteAll(t.rows)
+}
+
+func writeFile(path string, buf []byte) error {
+	return errors.Wrapf(os.WriteFile(path, buf, 0644), "writing %s", path)
+}
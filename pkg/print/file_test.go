@@ -0,0 +1,86 @@
+package print //nolint: predeclared
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/ojson"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteOutputsFile(t *testing.T) {
+	outputs := api.Outputs(ojson.MustNewValueFromJSON(`{
+		"users": [{"name": "alice", "age": 30}, {"name": "bob", "admin": true}],
+		"count": 2
+	}`))
+
+	t.Run("yaml keeps key order", func(t *testing.T) {
+		require := require.New(t)
+		path := filepath.Join(t.TempDir(), "outputs.yaml")
+		require.NoError(WriteOutputsFile(path, outputs))
+		buf, err := os.ReadFile(path)
+		require.NoError(err)
+		require.Equal(`users:
+    - name: alice
+      age: 30
+    - name: bob
+      admin: true
+count: 2
+`, string(buf))
+	})
+
+	t.Run("csv writes a file per named output", func(t *testing.T) {
+		require := require.New(t)
+		dir := t.TempDir()
+		require.NoError(WriteOutputsFile(filepath.Join(dir, "outputs.csv"), outputs))
+
+		buf, err := os.ReadFile(filepath.Join(dir, "outputs.users.csv"))
+		require.NoError(err)
+		require.Equal("name,age,admin\nalice,30,\nbob,,true\n", string(buf))
+
+		buf, err = os.ReadFile(filepath.Join(dir, "outputs.count.csv"))
+		require.NoError(err)
+		require.Equal("value\n2\n", string(buf))
+	})
+
+	t.Run("csv writes a single output to the given path", func(t *testing.T) {
+		require := require.New(t)
+		path := filepath.Join(t.TempDir(), "outputs.csv")
+		require.NoError(WriteOutputsFile(path, api.Outputs(ojson.MustNewValueFromJSON(`[1, "two"]`))))
+		buf, err := os.ReadFile(path)
+		require.NoError(err)
+		require.Equal("value\n1\ntwo\n", string(buf))
+	})
+
+	t.Run("csv writes an empty file without outputs", func(t *testing.T) {
+		require := require.New(t)
+		path := filepath.Join(t.TempDir(), "outputs.csv")
+		require.NoError(WriteOutputsFile(path, api.Outputs{}))
+		buf, err := os.ReadFile(path)
+		require.NoError(err)
+		require.Empty(buf)
+	})
+
+	t.Run("csv keeps output names inside the directory", func(t *testing.T) {
+		require := require.New(t)
+		dir := t.TempDir()
+		require.NoError(WriteOutputsFile(filepath.Join(dir, "outputs.csv"), api.Outputs(ojson.MustNewValueFromJSON(`{
+			"../escape": 1,
+			"a/b": 2
+		}`))))
+		require.FileExists(filepath.Join(dir, "outputs.___escape.csv"))
+		require.FileExists(filepath.Join(dir, "outputs.a_b.csv"))
+
+		err := WriteOutputsFile(filepath.Join(dir, "outputs.csv"), api.Outputs(ojson.MustNewValueFromJSON(`{
+			"a/b": 1,
+			"a.b": 2
+		}`)))
+		require.EqualError(err, `outputs "a/b" and "a.b" would both be written to a_b`)
+	})
+
+	t.Run("unknown extension", func(t *testing.T) {
+		require.Error(t, WriteOutputsFile(filepath.Join(t.TempDir(), "outputs.txt"), outputs))
+	})
+}
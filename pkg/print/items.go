@@ -0,0 +1,204 @@
+package print //nolint: predeclared
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/cli/pkg/logger"
+	libapi "github.com/airplanedev/lib/pkg/api"
+	"github.com/airplanedev/ojson"
+)
+
+// SortBy is the field that lists are sorted by before they are printed. Fields are named as
+// in the JSON output, e.g. "createdAt". If empty, lists are printed in the order they were
+// returned by the API.
+var SortBy string
+
+// itemFormatter implements the formatters that print every kind of value the same way, using
+// the same field names as the JSON formatter.
+type itemFormatter struct {
+	// write prints either a slice of items or a single item.
+	write        func(v interface{})
+	writeOutputs func(outputs api.Outputs)
+}
+
+// APIKeys implementation.
+func (f itemFormatter) apiKeys(apiKeys []api.APIKey) {
+	f.write(apiKeys)
+}
+
+// Tasks implementation.
+func (f itemFormatter) tasks(tasks []libapi.Task) {
+	f.write(printTasks(tasks))
+}
+
+// Task implementation.
+func (f itemFormatter) task(task libapi.Task) {
+	f.write(printTask(task))
+}
+
+// Runs implementation.
+func (f itemFormatter) runs(runs []api.Run) {
+	f.write(printRuns(runs))
+}
+
+// Run implementation.
+func (f itemFormatter) run(run api.Run) {
+	f.write(printRun(run))
+}
+
+// Outputs implementation.
+func (f itemFormatter) outputs(outputs api.Outputs, _ Truncation) {
+	f.writeOutputs(outputs)
+}
+
+// Config implementation.
+func (f itemFormatter) config(config api.Config) {
+	f.write(config)
+}
+
+// Deployments implementation.
+func (f itemFormatter) deployments(deployments []api.Deployment) {
+	f.write(deployments)
+}
+
+// Deployment implementation.
+func (f itemFormatter) deployment(deployment api.Deployment) {
+	f.write(deployment)
+}
+
+// toItems returns the elements of v if it is a slice, otherwise v itself.
+func toItems(v interface{}) []interface{} {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return []interface{}{v}
+	}
+	items := make([]interface{}, rv.Len())
+	for i := range items {
+		items[i] = rv.Index(i).Interface()
+	}
+	return items
+}
+
+// toRecord returns the JSON representation of v, keeping the order of object keys.
+func toRecord(v interface{}) interface{} {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		handleErr(err)
+		return nil
+	}
+	var value ojson.Value
+	handleErr(json.Unmarshal(buf, &value))
+	return value.V
+}
+
+// getField returns the value of the given field, matching it case-insensitively if there is
+// no exact match.
+func getField(record interface{}, field string) (interface{}, bool) {
+	o, ok := record.(*ojson.Object)
+	if !ok {
+		return nil, false
+	}
+	if v, ok := o.Get(field); ok {
+		return v, true
+	}
+	for _, key := range o.KeyOrder() {
+		if strings.EqualFold(key, field) {
+			return o.Get(key)
+		}
+	}
+	return nil, false
+}
+
+// recordsTable converts items into a table with one row per item. If columns is empty, a
+// column is included for every field.
+func recordsTable(items []interface{}, columns []string) outputTable {
+	records := make([]interface{}, len(items))
+	for i, item := range items {
+		records[i] = toRecord(item)
+	}
+	if len(columns) == 0 {
+		return valueToTable("", records)
+	}
+
+	t := outputTable{header: columns}
+	for _, record := range records {
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			v, _ := getField(record, column)
+			row[i] = getCellValue(v)
+		}
+		t.rows = append(t.rows, row)
+	}
+	return t
+}
+
+// sortItems sorts items by the SortBy field of their JSON representation. view converts an
+// item into the type that is printed, so that fields are named as in the JSON output.
+func sortItems[T any](items []T, view func(T) interface{}) []T {
+	if SortBy == "" || len(items) < 2 {
+		return items
+	}
+
+	keys := make([]interface{}, len(items))
+	found := false
+	for i, item := range items {
+		var ok bool
+		keys[i], ok = getField(toRecord(view(item)), SortBy)
+		found = found || ok
+	}
+	if !found {
+		logger.Warning("Unable to sort by %q: no such field.", SortBy)
+		return items
+	}
+
+	idx := make([]int, len(items))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		return lessValue(keys[idx[i]], keys[idx[j]])
+	})
+	sorted := make([]T, len(items))
+	for i, j := range idx {
+		sorted[i] = items[j]
+	}
+	return sorted
+}
+
+// lessValue orders JSON values. Missing values sort first, numbers are compared numerically
+// and everything else is compared by its string representation.
+func lessValue(a, b interface{}) bool {
+	switch {
+	case a == nil:
+		return b != nil
+	case b == nil:
+		return false
+	}
+	if fa, ok := a.(float64); ok {
+		if fb, ok := b.(float64); ok {
+			return fa < fb
+		}
+	}
+	return getCellValue(a) < getCellValue(b)
+}
+
+func identity[T any](v T) interface{} {
+	return v
+}
+
+// toJSONValue returns the JSON representation of v using plain maps and slices.
+func toJSONValue(v interface{}) (interface{}, error) {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out interface{}
+	if err := json.Unmarshal(buf, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,74 @@
+package print //nolint: predeclared
+
+import (
+	"testing"
+	"time"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortItems(t *testing.T) {
+	require := require.New(t)
+	defer func(sortBy string) { SortBy = sortBy }(SortBy)
+
+	now := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	keys := []api.APIKey{
+		{ID: "key2", Name: "b", CreatedAt: now.Add(time.Hour)},
+		{ID: "key1", Name: "c", CreatedAt: now},
+		{ID: "key3", Name: "a", CreatedAt: now.Add(2 * time.Hour)},
+	}
+	ids := func(keys []api.APIKey) []string {
+		var ids []string
+		for _, k := range keys {
+			ids = append(ids, k.ID)
+		}
+		return ids
+	}
+
+	SortBy = ""
+	require.Equal([]string{"key2", "key1", "key3"}, ids(sortItems(keys, identity[api.APIKey])))
+
+	SortBy = "name"
+	require.Equal([]string{"key3", "key2", "key1"}, ids(sortItems(keys, identity[api.APIKey])))
+
+	// Fields are matched case-insensitively.
+	SortBy = "CreatedAt"
+	require.Equal([]string{"key1", "key2", "key3"}, ids(sortItems(keys, identity[api.APIKey])))
+
+	// Unknown fields leave the order unchanged.
+	SortBy = "unknown"
+	require.Equal([]string{"key2", "key1", "key3"}, ids(sortItems(keys, identity[api.APIKey])))
+}
+
+func TestRecordsTable(t *testing.T) {
+	require := require.New(t)
+
+	configs := []api.Config{
+		{Name: "db_url", Tag: "prod", Value: "postgres://"},
+		{Name: "token", IsSecret: true},
+	}
+
+	table := recordsTable(toItems(configs), nil)
+	require.Equal([]string{"name", "tag", "value", "isSecret"}, table.header)
+	require.Equal([][]string{
+		{"db_url", "prod", "postgres://", "false"},
+		{"token", "", "", "true"},
+	}, table.rows)
+
+	table = recordsTable(toItems(configs[0]), []string{"isSecret", "Name", "missing"})
+	require.Equal([]string{"isSecret", "Name", "missing"}, table.header)
+	require.Equal([][]string{{"false", "db_url", ""}}, table.rows)
+}
+
+func TestNormalizeJSONPath(t *testing.T) {
+	for in, out := range map[string]string{
+		"$[*].slug":   "$[*].slug",
+		"{.slug}":     "$.slug",
+		"{[*].slug}":  "$[*].slug",
+		"slug":        "$.slug",
+		" .tasks[0] ": "$.tasks[0]",
+	} {
+		require.Equal(t, out, normalizeJSONPath(in), in)
+	}
+}
@@ -52,7 +52,7 @@ func (j *JSON) run(run api.Run) {
 }
 
 // Outputs implementation.
-func (j *JSON) outputs(outputs api.Outputs) {
+func (j *JSON) outputs(outputs api.Outputs, _ Truncation) {
 	handleErr(j.enc.Encode(ojson.Value(outputs)))
 }
 
@@ -60,3 +60,13 @@ func (j *JSON) outputs(outputs api.Outputs) {
 func (j *JSON) config(config api.Config) {
 	handleErr(j.enc.Encode(config))
 }
+
+// Deployments implementation.
+func (j *JSON) deployments(deployments []api.Deployment) {
+	handleErr(j.enc.Encode(deployments))
+}
+
+// Deployment implementation.
+func (j *JSON) deployment(deployment api.Deployment) {
+	handleErr(j.enc.Encode(deployment))
+}
@@ -0,0 +1,32 @@
+package print //nolint: predeclared
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/ojson"
+)
+
+// NewJSONLinesFormatter returns a formatter that prints each item of a list as a JSON object
+// on its own line.
+func NewJSONLinesFormatter() Formatter {
+	enc := json.NewEncoder(os.Stdout)
+	return itemFormatter{
+		write: func(v interface{}) {
+			for _, item := range toItems(v) {
+				handleErr(enc.Encode(item))
+			}
+		},
+		writeOutputs: func(outputs api.Outputs) {
+			values, ok := outputs.V.([]interface{})
+			if !ok {
+				handleErr(enc.Encode(ojson.Value(outputs)))
+				return
+			}
+			for _, v := range values {
+				handleErr(enc.Encode(ojson.Value{V: v}))
+			}
+		},
+	}
+}
@@ -1,7 +1,9 @@
 package print //nolint: predeclared
 
 import (
+	"regexp"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/airplanedev/cli/pkg/api"
 	"github.com/airplanedev/cli/pkg/logger"
@@ -23,18 +25,20 @@ type Formatter interface {
 	task(libapi.Task)
 	runs([]api.Run)
 	run(api.Run)
-	outputs(api.Outputs)
+	outputs(api.Outputs, Truncation)
 	config(api.Config)
+	deployments([]api.Deployment)
+	deployment(api.Deployment)
 }
 
 // APIKeys prints one or more API keys.
 func APIKeys(apiKeys []api.APIKey) {
-	DefaultFormatter.apiKeys(apiKeys)
+	DefaultFormatter.apiKeys(sortItems(apiKeys, identity[api.APIKey]))
 }
 
 // Tasks prints the given slice of tasks using the default formatter.
 func Tasks(tasks []libapi.Task) {
-	DefaultFormatter.tasks(tasks)
+	DefaultFormatter.tasks(sortItems(tasks, func(t libapi.Task) interface{} { return printTask(t) }))
 }
 
 // Task prints a single task.
@@ -44,7 +48,7 @@ func Task(task libapi.Task) {
 
 // Runs prints the given runs.
 func Runs(runs []api.Run) {
-	DefaultFormatter.runs(runs)
+	DefaultFormatter.runs(sortItems(runs, func(r api.Run) interface{} { return printRun(r) }))
 }
 
 // Run prints a single run.
@@ -52,9 +56,9 @@ func Run(run api.Run) {
 	DefaultFormatter.run(run)
 }
 
-// Outputs prints a collection of outputs.
-func Outputs(outputs api.Outputs) {
-	DefaultFormatter.outputs(outputs)
+// Outputs prints a collection of outputs. Only the table formatter truncates them.
+func Outputs(outputs api.Outputs, t Truncation) {
+	DefaultFormatter.outputs(outputs, t)
 }
 
 // Config prints a single config var.
@@ -62,8 +66,18 @@ func Config(config api.Config) {
 	DefaultFormatter.config(config)
 }
 
+// Deployments prints the given deployments.
+func Deployments(deployments []api.Deployment) {
+	DefaultFormatter.deployments(sortItems(deployments, identity[api.Deployment]))
+}
+
+// Deployment prints a single deployment.
+func Deployment(deployment api.Deployment) {
+	DefaultFormatter.deployment(deployment)
+}
+
 // Print outputs obj based on DefaultFormatter
-// If JSON or YAML, uses that formatter to encode obj
+// If JSON, YAML, CSV, JSON Lines, a template or a JSONPath, uses that formatter to encode obj
 // Otherwise, calls defaultPrintFunc to render the obj
 func Print(obj interface{}, defaultPrintFunc func()) {
 	switch f := DefaultFormatter.(type) {
@@ -71,6 +85,8 @@ func Print(obj interface{}, defaultPrintFunc func()) {
 		f.Encode(obj)
 	case YAML:
 		f.Encode(obj)
+	case itemFormatter:
+		f.write(obj)
 	default:
 		defaultPrintFunc()
 	}
@@ -89,19 +105,27 @@ func BoxPrintWithPrefix(s, prefix string) {
 		lines := strings.Split(s, "\n")
 		sLen := 0
 		for _, line := range lines {
-			if len(line) > sLen {
-				sLen = len(line)
+			if visibleLen(line) > sLen {
+				sLen = visibleLen(line)
 			}
 		}
 		logger.Log(prefix + "+" + strings.Repeat("-", sLen+2) + "+")
 		for _, line := range lines {
-			padding := strings.Repeat(" ", sLen-len(line))
+			padding := strings.Repeat(" ", sLen-visibleLen(line))
 			logger.Log(prefix + "| " + line + padding + " |")
 		}
 		logger.Log(prefix + "+" + strings.Repeat("-", sLen+2) + "+")
 	})
 }
 
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// visibleLen returns the number of characters in s that take up space in a terminal, ignoring
+// color codes.
+func visibleLen(s string) int {
+	return utf8.RuneCountInString(ansiEscape.ReplaceAllString(s, ""))
+}
+
 func handleErr(err error) {
 	if err != nil {
 		logger.Error("failed to print output: %+v", err)
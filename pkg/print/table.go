@@ -20,10 +20,32 @@ import (
 // Table implements a table formatter.
 //
 // Its zero-value is ready for use.
-type Table struct{}
+type Table struct {
+	// Columns replaces the default columns with the given fields, named as in the JSON output.
+	Columns []string
+}
+
+// printColumns prints v as a table with the configured columns. It returns false if no
+// columns were configured.
+func (t Table) printColumns(v interface{}) bool {
+	if len(t.Columns) == 0 {
+		return false
+	}
+	table := recordsTable(toItems(v), t.Columns)
+	tw := tablewriter.NewWriter(os.Stdout)
+	tw.SetBorder(false)
+	tw.SetAutoFormatHeaders(false)
+	tw.SetHeader(table.header)
+	tw.AppendBulk(table.rows)
+	tw.Render()
+	return true
+}
 
 // APIKeys implementation.
 func (t Table) apiKeys(apiKeys []api.APIKey) {
+	if t.printColumns(apiKeys) {
+		return
+	}
 	tw := tablewriter.NewWriter(os.Stdout)
 	tw.SetBorder(false)
 	tw.SetHeader([]string{"id", "created at", "name"})
@@ -41,6 +63,9 @@ func (t Table) apiKeys(apiKeys []api.APIKey) {
 
 // Tasks implementation.
 func (t Table) tasks(tasks []libapi.Task) {
+	if t.printColumns(printTasks(tasks)) {
+		return
+	}
 	tw := tablewriter.NewWriter(os.Stdout)
 	tw.SetBorder(false)
 	tw.SetHeader([]string{"name", "slug", "builder", "parameters"})
@@ -87,6 +112,9 @@ func (t Table) tasks(tasks []libapi.Task) {
 
 // Task implementation.
 func (t Table) task(task libapi.Task) {
+	if t.printColumns(printTask(task)) {
+		return
+	}
 	builderStr := task.Kind
 
 	fmt.Fprintln(os.Stdout, "Name:       ", task.Name)
@@ -129,6 +157,9 @@ func (t Table) task(task libapi.Task) {
 
 // Runs implementation.
 func (t Table) runs(runs []api.Run) {
+	if t.printColumns(printRuns(runs)) {
+		return
+	}
 	tw := tablewriter.NewWriter(os.Stdout)
 	tw.SetBorder(false)
 	tw.SetHeader([]string{"id", "task", "status", "created at", "ended at"})
@@ -163,7 +194,7 @@ func (t Table) run(run api.Run) {
 }
 
 // print outputs as table
-func (t Table) outputs(outputs api.Outputs) {
+func (Table) outputs(outputs api.Outputs, tr Truncation) {
 	// Sort the output keys to match the UI.
 	switch t := outputs.V.(type) {
 	case *ojson.Object:
@@ -176,20 +207,20 @@ func (t Table) outputs(outputs api.Outputs) {
 			case []interface{}:
 				ok, jsonObjects := parseArrayOfJsonObject(t2)
 				if ok {
-					printOutputTable(jsonObjects)
+					printOutputTable(jsonObjects, tr)
 				} else {
-					printOutputArray(t2)
+					printOutputArray(t2, tr)
 				}
 			default:
-				fmt.Fprintln(os.Stdout, getCellValue(t2))
+				fmt.Fprintln(os.Stdout, tr.value(getCellValue(t2)))
 			}
 		}
 	case []interface{}:
 		ok, jsonObjects := parseArrayOfJsonObject(t)
 		if ok {
-			printOutputTable(jsonObjects)
+			printOutputTable(jsonObjects, tr)
 		} else {
-			printOutputArray(t)
+			printOutputArray(t, tr)
 		}
 	default:
 		if v, err := json.Marshal(t); err != nil {
@@ -213,7 +244,7 @@ func parseArrayOfJsonObject(values []interface{}) (bool, []*ojson.Object) {
 	return true, jsonObjects
 }
 
-func printOutputTable(objects []*ojson.Object) {
+func printOutputTable(objects []*ojson.Object, t Truncation) {
 	keyMap := make(map[string]bool)
 	var keyList []string
 	for _, object := range objects {
@@ -228,23 +259,33 @@ func printOutputTable(objects []*ojson.Object) {
 
 	tw := newTableWriter()
 	tw.SetHeader(keyList)
-	for _, object := range objects {
+	shown, elided := t.rows(len(objects))
+	for _, object := range objects[:shown] {
 		values := make([]string, len(keyList))
 		for i, key := range keyList {
 			v, _ := object.Get(key)
-			values[i] = getCellValue(v)
+			values[i] = t.value(getCellValue(v))
 		}
 		tw.Append(values)
 	}
 	tw.Render()
+	printElidedRows(elided)
 }
 
-func printOutputArray(values []interface{}) {
+func printOutputArray(values []interface{}, t Truncation) {
 	tw := newTableWriter()
-	for _, value := range values {
-		tw.Append([]string{getCellValue(value)})
+	shown, elided := t.rows(len(values))
+	for _, value := range values[:shown] {
+		tw.Append([]string{t.value(getCellValue(value))})
 	}
 	tw.Render()
+	printElidedRows(elided)
+}
+
+func printElidedRows(elided int) {
+	if elided > 0 {
+		fmt.Fprintln(os.Stdout, logger.Gray("... %d more rows (use -o json or --outputs-file to see all of them)", elided))
+	}
 }
 
 func newTableWriter() *tablewriter.Table {
@@ -278,6 +319,9 @@ func getCellValue(value interface{}) string {
 
 // print config as table
 func (t Table) config(config api.Config) {
+	if t.printColumns(config) {
+		return
+	}
 	// Nothing fancy, just the value
 	var valueStr string
 	if config.IsSecret {
@@ -287,3 +331,49 @@ func (t Table) config(config api.Config) {
 	}
 	fmt.Fprintln(os.Stdout, valueStr)
 }
+
+// Deployments implementation.
+func (t Table) deployments(deployments []api.Deployment) {
+	if t.printColumns(deployments) {
+		return
+	}
+	tw := tablewriter.NewWriter(os.Stdout)
+	tw.SetBorder(false)
+	tw.SetHeader([]string{"id", "status", "created at", "created by", "ended at"})
+
+	for _, d := range deployments {
+		var endedAt string
+		switch {
+		case d.SucceededAt != nil:
+			endedAt = d.SucceededAt.Format(time.RFC3339)
+		case d.FailedAt != nil:
+			endedAt = d.FailedAt.Format(time.RFC3339)
+		case d.CancelledAt != nil:
+			endedAt = d.CancelledAt.Format(time.RFC3339)
+		}
+
+		tw.Append([]string{
+			d.ID,
+			d.Status(),
+			d.CreatedAt.Format(time.RFC3339),
+			d.CreatedBy,
+			endedAt,
+		})
+	}
+
+	tw.Render()
+}
+
+// Deployment implementation.
+func (t Table) deployment(deployment api.Deployment) {
+	if t.printColumns(deployment) {
+		return
+	}
+	fmt.Fprintln(os.Stdout, "ID:        ", deployment.ID)
+	fmt.Fprintln(os.Stdout, "Status:    ", deployment.Status())
+	fmt.Fprintln(os.Stdout, "Created at:", deployment.CreatedAt.Format(time.RFC3339))
+	fmt.Fprintln(os.Stdout, "Created by:", deployment.CreatedBy)
+	if deployment.FailedReason != "" {
+		fmt.Fprintln(os.Stdout, "Reason:    ", deployment.FailedReason)
+	}
+}
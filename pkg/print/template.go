@@ -0,0 +1,104 @@
+package print //nolint: predeclared
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/airplanedev/cli/pkg/api"
+	"github.com/airplanedev/ojson"
+	"github.com/pkg/errors"
+	"github.com/yalp/jsonpath"
+)
+
+// NewTemplateFormatter returns a formatter that renders each item of a list with a Go
+// template, e.g. `{{.Slug}}`. Each rendered item is printed on its own line.
+func NewTemplateFormatter(text string) (Formatter, error) {
+	tmpl, err := template.New("output").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			buf, err := json.Marshal(v)
+			return string(buf), err
+		},
+		"join": strings.Join,
+	}).Parse(text)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing output template")
+	}
+
+	render := func(items []interface{}) {
+		for _, item := range items {
+			var b bytes.Buffer
+			if err := tmpl.Execute(&b, item); err != nil {
+				handleErr(errors.Wrap(err, "executing output template"))
+				return
+			}
+			fmt.Fprintln(os.Stdout, strings.TrimSuffix(b.String(), "\n"))
+		}
+	}
+	return itemFormatter{
+		write: func(v interface{}) {
+			render(toItems(v))
+		},
+		writeOutputs: func(outputs api.Outputs) {
+			// Outputs do not have a Go type, so templates access their fields by key, e.g. `{{.name}}`.
+			v, err := toJSONValue(ojson.Value(outputs))
+			if err != nil {
+				handleErr(err)
+				return
+			}
+			render(toItems(v))
+		},
+	}, nil
+}
+
+// NewJSONPathFormatter returns a formatter that prints the result of evaluating a JSONPath
+// expression against the JSON output, e.g. `$[*].slug`. If the result is a list, each element
+// is printed on its own line.
+func NewJSONPathFormatter(expr string) (Formatter, error) {
+	filter, err := jsonpath.Prepare(normalizeJSONPath(expr))
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing jsonpath %q", expr)
+	}
+
+	eval := func(v interface{}) {
+		value, err := toJSONValue(v)
+		if err != nil {
+			handleErr(err)
+			return
+		}
+		result, err := filter(value)
+		if err != nil {
+			handleErr(errors.Wrapf(err, "evaluating jsonpath %q", expr))
+			return
+		}
+		for _, item := range toItems(result) {
+			fmt.Fprintln(os.Stdout, getCellValue(item))
+		}
+	}
+	return itemFormatter{
+		write: eval,
+		writeOutputs: func(outputs api.Outputs) {
+			eval(ojson.Value(outputs))
+		},
+	}, nil
+}
+
+// normalizeJSONPath accepts kubectl-style expressions such as `{.slug}` as well as expressions
+// that leave out the leading `$`.
+func normalizeJSONPath(expr string) string {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "{") && strings.HasSuffix(expr, "}") {
+		expr = strings.TrimSpace(expr[1 : len(expr)-1])
+	}
+	switch {
+	case strings.HasPrefix(expr, "$"):
+		return expr
+	case strings.HasPrefix(expr, "."), strings.HasPrefix(expr, "["):
+		return "$" + expr
+	default:
+		return "$." + expr
+	}
+}
@@ -62,6 +62,7 @@ type printRun struct {
 	CancelledAt *time.Time    `json:"cancelledAt" yaml:"cancelledAt"`
 	CancelledBy *string       `json:"cancelledBy" yaml:"cancelledBy"`
 	EnvSlug     string        `json:"envSlug" yaml:"envSlug"`
+	ParentID    string        `json:"parentID" yaml:"parentID"`
 }
 
 func printRuns(runs []api.Run) []printRun {
@@ -71,3 +72,26 @@ func printRuns(runs []api.Run) []printRun {
 	}
 	return pruns
 }
+
+// This struct mirrors api.Deployment, but with yaml tags.
+type printDeployment struct {
+	ID           string           `yaml:"id"`
+	TeamID       string           `yaml:"teamID"`
+	CreatedAt    time.Time        `yaml:"createdAt"`
+	CreatedBy    string           `yaml:"createdBy"`
+	SucceededAt  *time.Time       `yaml:"succeededAt,omitempty"`
+	CancelledAt  *time.Time       `yaml:"cancelledAt,omitempty"`
+	FailedAt     *time.Time       `yaml:"failedAt,omitempty"`
+	FailedReason string           `yaml:"failedReason,omitempty"`
+	GitMetadata  *api.GitMetadata `yaml:"gitMetadata,omitempty"`
+	Tasks        []libapi.Task    `yaml:"tasks,omitempty"`
+	Views        []api.DeployView `yaml:"views,omitempty"`
+}
+
+func printDeployments(deployments []api.Deployment) []printDeployment {
+	pds := make([]printDeployment, len(deployments))
+	for i, d := range deployments {
+		pds[i] = printDeployment(d)
+	}
+	return pds
+}
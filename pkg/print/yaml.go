@@ -44,7 +44,7 @@ func (YAML) run(run api.Run) {
 }
 
 // Outputs implementation.
-func (YAML) outputs(outputs api.Outputs) {
+func (YAML) outputs(outputs api.Outputs, _ Truncation) {
 	// TODO: update ojson to handle yaml properly
 	handleErr(yaml.NewEncoder(os.Stdout).Encode(outputs.V))
 }
@@ -53,3 +53,13 @@ func (YAML) outputs(outputs api.Outputs) {
 func (YAML) config(config api.Config) {
 	handleErr(yaml.NewEncoder(os.Stdout).Encode(config))
 }
+
+// Deployments implementation.
+func (YAML) deployments(deployments []api.Deployment) {
+	handleErr(yaml.NewEncoder(os.Stdout).Encode(printDeployments(deployments)))
+}
+
+// Deployment implementation.
+func (YAML) deployment(deployment api.Deployment) {
+	handleErr(yaml.NewEncoder(os.Stdout).Encode(printDeployment(deployment)))
+}
@@ -95,6 +95,7 @@ func ExecuteTaskHandler(ctx context.Context, state *state.State, r *http.Request
 			IsBuiltin:   isBuiltin,
 			AuthInfo:    state.AuthInfo,
 			LogBroker:   run.LogBroker,
+			NoTruncate:  state.NoTruncate,
 		}
 		resourceAttachments := map[string]string{}
 		mergedResources, err := resource.MergeRemoteResources(ctx, state)
@@ -400,9 +401,8 @@ func CreateDisplayHandler(ctx context.Context, state *state.State, r *http.Reque
 		return CreateDisplayResponse{}, err
 	}
 
-	content := fmt.Sprintf("[kind=%s]\n\n%s", display.Kind, display.Content)
 	prefix := "[" + logger.Gray(run.TaskID+" display") + "] "
-	print.BoxPrintWithPrefix(content, prefix)
+	print.BoxPrintWithPrefix(print.RenderDisplay(display, print.NewTruncation(state.NoTruncate)), prefix)
 
 	return CreateDisplayResponse{
 		Display: display,
@@ -91,6 +91,8 @@ type Options struct {
 	DevConfig *conf.DevConfig
 	Dir       string
 	AuthInfo  api.AuthInfoResponse
+	// NoTruncate prints the outputs and displays of runs in full.
+	NoTruncate bool
 }
 
 // newServer returns a new HTTP server with API routes
@@ -120,6 +122,7 @@ func Start(opts Options) (*Server, error) {
 		Dir:         opts.Dir,
 		Logger:      logger.NewStdErrLogger(logger.StdErrLoggerOpts{}),
 		AuthInfo:    opts.AuthInfo,
+		NoTruncate:  opts.NoTruncate,
 	}
 
 	r := NewRouter(state)
@@ -36,6 +36,8 @@ type State struct {
 
 	AuthInfo     api.AuthInfoResponse
 	VersionCache version.Cache
+	// NoTruncate prints the outputs and displays of runs in full.
+	NoTruncate bool
 }
 
 // TODO: add limit on max items
@@ -0,0 +1,72 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestFile is the name of the manifest at the root of a template. It is not copied when the
+// template is initialized.
+const ManifestFile = "airplane.template.yaml"
+
+// Manifest describes a template and the variables that are substituted into it, e.g.:
+//
+//	name: Internal service
+//	description: A task and a view for a service.
+//	variables:
+//	  - name: service
+//	    description: The name of the service
+//	  - name: team
+//	    default: platform
+//
+// Variables are referenced as {{service}} in the contents and names of the template's files.
+type Manifest struct {
+	Name        string     `yaml:"name"`
+	Description string     `yaml:"description"`
+	Variables   []Variable `yaml:"variables"`
+}
+
+// Variable is a value that is prompted for when a template is initialized.
+type Variable struct {
+	Name        string `yaml:"name" json:"name"`
+	Description string `yaml:"description" json:"description,omitempty"`
+	// Default is used if no value is given. Variables without a default are required.
+	Default string `yaml:"default" json:"default,omitempty"`
+}
+
+var variableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ReadManifest reads the manifest of the template in dir. Templates without a manifest have no
+// variables and are named after dir.
+func ReadManifest(dir string) (Manifest, error) {
+	path := filepath.Join(dir, ManifestFile)
+	buf, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Manifest{Name: filepath.Base(dir)}, nil
+	} else if err != nil {
+		return Manifest{}, errors.Wrap(err, "reading template manifest")
+	}
+
+	var m Manifest
+	if err := yaml.Unmarshal(buf, &m); err != nil {
+		return Manifest{}, errors.Wrapf(err, "parsing %s", path)
+	}
+	if m.Name == "" {
+		m.Name = filepath.Base(dir)
+	}
+	seen := map[string]bool{}
+	for _, v := range m.Variables {
+		if !variableName.MatchString(v.Name) {
+			return Manifest{}, errors.Errorf("%s: invalid variable name %q", path, v.Name)
+		}
+		if seen[v.Name] {
+			return Manifest{}, errors.Errorf("%s: variable %s is declared more than once", path, v.Name)
+		}
+		seen[v.Name] = true
+	}
+	return m, nil
+}
@@ -0,0 +1,332 @@
+// Package templates initializes projects from local templates: directories or tarballs with an
+// optional manifest of variables that are substituted into the template's files.
+package templates
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/airplanedev/cli/pkg/utils"
+	"github.com/pkg/errors"
+)
+
+// Template is a local template.
+type Template struct {
+	// Slug is the name that the template is initialized with, i.e. the name of its directory or
+	// tarball without an extension.
+	Slug        string     `json:"slug" yaml:"slug"`
+	Name        string     `json:"name" yaml:"name"`
+	Description string     `json:"description,omitempty" yaml:"description,omitempty"`
+	Path        string     `json:"path" yaml:"path"`
+	Variables   []Variable `json:"variables,omitempty" yaml:"variables,omitempty"`
+}
+
+var tarballExts = []string{".tar.gz", ".tgz", ".tar"}
+
+// IsTarball returns true if path has the extension of a tarball.
+func IsTarball(path string) bool {
+	return tarballExt(path) != ""
+}
+
+func tarballExt(path string) string {
+	for _, ext := range tarballExts {
+		if strings.HasSuffix(strings.ToLower(path), ext) {
+			return ext
+		}
+	}
+	return ""
+}
+
+// List returns the templates in dir, sorted by slug. A dir that does not exist has no templates.
+func List(dir string) ([]Template, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "reading templates directory")
+	}
+
+	var templates []Template
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".") || !(e.IsDir() || IsTarball(e.Name())) {
+			continue
+		}
+		t, err := Load(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Slug < templates[j].Slug
+	})
+	return templates, nil
+}
+
+// Find returns the template that ref refers to: either the path of a directory or tarball, or
+// the slug of a template in dir. It returns false if there is no such local template.
+func Find(dir, ref string) (Template, bool, error) {
+	if info, err := os.Stat(ref); err == nil && (info.IsDir() || IsTarball(ref)) {
+		t, err := Load(ref)
+		return t, err == nil, err
+	}
+	if dir == "" || strings.ContainsAny(ref, `/\`) {
+		return Template{}, false, nil
+	}
+	for _, name := range append([]string{ref}, withExts(ref)...) {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && (info.IsDir() || IsTarball(path)) {
+			t, err := Load(path)
+			return t, err == nil, err
+		}
+	}
+	return Template{}, false, nil
+}
+
+func withExts(slug string) []string {
+	names := make([]string, len(tarballExts))
+	for i, ext := range tarballExts {
+		names[i] = slug + ext
+	}
+	return names
+}
+
+// Load reads the manifest of the template at path.
+func Load(path string) (Template, error) {
+	dir, closer, err := Open(path)
+	if err != nil {
+		return Template{}, err
+	}
+	defer closer.Close()
+
+	m, err := ReadManifest(dir)
+	if err != nil {
+		return Template{}, err
+	}
+	slug := filepath.Base(path)
+	slug = slug[:len(slug)-len(tarballExt(slug))]
+	if m.Name == filepath.Base(dir) {
+		// Templates without a name are named after their slug, not a temporary directory.
+		m.Name = slug
+	}
+	return Template{
+		Slug:        slug,
+		Name:        m.Name,
+		Description: m.Description,
+		Path:        path,
+		Variables:   m.Variables,
+	}, nil
+}
+
+// Open returns the directory of the template at path, extracting it first if it is a tarball.
+// The closer removes any extracted files.
+func Open(path string) (string, io.Closer, error) {
+	if !IsTarball(path) {
+		return path, utils.CloseFunc(func() error { return nil }), nil
+	}
+
+	tmpdir, err := os.MkdirTemp("", "airplane-template-*")
+	if err != nil {
+		return "", nil, errors.Wrap(err, "creating temporary directory")
+	}
+	closer := utils.CloseFunc(func() error {
+		return os.RemoveAll(tmpdir)
+	})
+	if err := extract(path, tmpdir); err != nil {
+		closer.Close()
+		return "", nil, err
+	}
+
+	// Tarballs often contain a single top-level directory with the template.
+	dir := tmpdir
+	entries, err := os.ReadDir(tmpdir)
+	if err != nil {
+		closer.Close()
+		return "", nil, errors.Wrap(err, "reading extracted template")
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		dir = filepath.Join(tmpdir, entries[0].Name())
+	}
+	return dir, closer, nil
+}
+
+// CheckDestination returns an error if initializing t into dir would overwrite the template
+// itself, i.e. if dir is the template or contains it.
+func CheckDestination(t Template, dir string) error {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return errors.Wrapf(err, "resolving %s", dir)
+	}
+	absTemplate, err := filepath.Abs(t.Path)
+	if err != nil {
+		return errors.Wrapf(err, "resolving %s", t.Path)
+	}
+	rel, err := filepath.Rel(absDir, absTemplate)
+	if err != nil {
+		return errors.Wrapf(err, "resolving %s", t.Path)
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil
+	}
+	return errors.Errorf("cannot initialize template %s into %s, which would overwrite the template: run airplane init from another directory", t.Slug, dir)
+}
+
+// extract extracts the tarball at path into dir.
+func extract(path, dir string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return errors.Wrap(err, "opening template")
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if ext := tarballExt(path); ext == ".tar.gz" || ext == ".tgz" {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return errors.Wrapf(err, "reading %s", path)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return errors.Wrapf(err, "reading %s", path)
+		}
+
+		// Entries must not escape dir.
+		name := filepath.Clean(filepath.FromSlash(hdr.Name))
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			return errors.Errorf("%s: invalid path %s", path, hdr.Name)
+		}
+		target := filepath.Join(dir, name)
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return errors.Wrapf(err, "creating %s", target)
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return errors.Wrapf(err, "creating %s", filepath.Dir(target))
+			}
+			out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, fs.FileMode(hdr.Mode).Perm())
+			if err != nil {
+				return errors.Wrapf(err, "creating %s", target)
+			}
+			if _, err := io.Copy(out, tr); err != nil {
+				out.Close()
+				return errors.Wrapf(err, "extracting %s", hdr.Name)
+			}
+			if err := out.Close(); err != nil {
+				return errors.Wrapf(err, "extracting %s", hdr.Name)
+			}
+		default:
+			// Links and other special files are not supported in templates.
+		}
+	}
+}
+
+// ResolveValues returns the value of each variable: the given value, else the prompted value if
+// prompt is set, else its default. It errors if a required variable has no value or if a value
+// is given for an unknown variable.
+func ResolveValues(vars []Variable, given map[string]string, prompt func(Variable) (string, error)) (map[string]string, error) {
+	known := map[string]bool{}
+	for _, v := range vars {
+		known[v.Name] = true
+	}
+	for name := range given {
+		if !known[name] {
+			return nil, errors.Errorf("unknown template variable %s", name)
+		}
+	}
+
+	values := map[string]string{}
+	for _, v := range vars {
+		value, ok := given[v.Name]
+		if !ok && prompt != nil {
+			var err error
+			if value, err = prompt(v); err != nil {
+				return nil, err
+			}
+		}
+		if value == "" {
+			value = v.Default
+		}
+		if value == "" {
+			return nil, errors.Errorf("missing a value for template variable %s: use --var %s=<value>", v.Name, v.Name)
+		}
+		values[v.Name] = value
+	}
+	return values, nil
+}
+
+var reference = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// Substitute replaces references to variables such as {{name}} with their values. References to
+// undeclared variables are left as-is, so that other uses of braces are not affected.
+func Substitute(s string, values map[string]string) string {
+	return reference.ReplaceAllStringFunc(s, func(match string) string {
+		name := reference.FindStringSubmatch(match)[1]
+		if value, ok := values[name]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+// Render copies the template in srcDir into dstDir, substituting values into the contents and
+// names of its files. The manifest is not copied. It returns the paths of the files it created.
+func Render(srcDir, dstDir string, values map[string]string) ([]string, error) {
+	var created []string
+	err := filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." || rel == ManifestFile {
+			return nil
+		}
+
+		target := filepath.Join(dstDir, Substitute(rel, values))
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		buf, err := os.ReadFile(path)
+		if err != nil {
+			return errors.Wrapf(err, "reading %s", path)
+		}
+		// Binary files are copied as-is.
+		if !bytes.ContainsRune(buf, 0) {
+			buf = []byte(Substitute(string(buf), values))
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return errors.Wrapf(err, "creating %s", filepath.Dir(target))
+		}
+		if err := os.WriteFile(target, buf, info.Mode().Perm()); err != nil {
+			return errors.Wrapf(err, "writing %s", target)
+		}
+		created = append(created, target)
+		return nil
+	})
+	return created, err
+}
@@ -0,0 +1,154 @@
+package templates
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testManifest = `name: Service
+description: A task for a service.
+variables:
+  - name: service
+    description: The name of the service
+  - name: team
+    default: platform
+`
+
+func writeTemplate(t *testing.T, dir string) {
+	require := require.New(t)
+	require.NoError(os.MkdirAll(filepath.Join(dir, "{{service}}"), 0755))
+	require.NoError(os.WriteFile(filepath.Join(dir, ManifestFile), []byte(testManifest), 0644))
+	require.NoError(os.WriteFile(filepath.Join(dir, "{{service}}", "{{service}}.task.yaml"), []byte("slug: {{ service }}_restart\nowner: {{team}}\nstyle: {{style}}\n"), 0644))
+}
+
+func TestSubstitute(t *testing.T) {
+	require := require.New(t)
+
+	values := map[string]string{"service": "billing"}
+	require.Equal("billing/billing.ts", Substitute("{{service}}/{{ service }}.ts", values))
+	// Undeclared variables and other braces are left as-is.
+	require.Equal("<div style={{ color: 1 }}>{{other}}</div>", Substitute("<div style={{ color: 1 }}>{{other}}</div>", values))
+}
+
+func TestResolveValues(t *testing.T) {
+	require := require.New(t)
+	vars := []Variable{{Name: "service"}, {Name: "team", Default: "platform"}}
+
+	values, err := ResolveValues(vars, map[string]string{"service": "billing"}, nil)
+	require.NoError(err)
+	require.Equal(map[string]string{"service": "billing", "team": "platform"}, values)
+
+	values, err = ResolveValues(vars, nil, func(v Variable) (string, error) {
+		if v.Name == "service" {
+			return "search", nil
+		}
+		return "", nil
+	})
+	require.NoError(err)
+	require.Equal(map[string]string{"service": "search", "team": "platform"}, values)
+
+	_, err = ResolveValues(vars, nil, nil)
+	require.EqualError(err, "missing a value for template variable service: use --var service=<value>")
+
+	_, err = ResolveValues(vars, map[string]string{"service": "billing", "region": "us"}, nil)
+	require.EqualError(err, "unknown template variable region")
+}
+
+func TestRender(t *testing.T) {
+	require := require.New(t)
+	src, dst := t.TempDir(), t.TempDir()
+	writeTemplate(t, src)
+
+	created, err := Render(src, dst, map[string]string{"service": "billing", "team": "payments"})
+	require.NoError(err)
+	require.Equal([]string{filepath.Join(dst, "billing", "billing.task.yaml")}, created)
+
+	buf, err := os.ReadFile(filepath.Join(dst, "billing", "billing.task.yaml"))
+	require.NoError(err)
+	require.Equal("slug: billing_restart\nowner: payments\nstyle: {{style}}\n", string(buf))
+	require.NoFileExists(filepath.Join(dst, ManifestFile))
+}
+
+func TestListAndFind(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	writeTemplate(t, filepath.Join(dir, "service"))
+	require.NoError(os.MkdirAll(filepath.Join(dir, "empty"), 0755))
+	writeTarball(t, filepath.Join(dir, "packed.tar.gz"), map[string]string{
+		"packed/" + ManifestFile: "name: Packed\n",
+		"packed/task.sql":        "SELECT 1",
+	})
+
+	templates, err := List(dir)
+	require.NoError(err)
+	require.Len(templates, 3)
+	require.Equal("empty", templates[0].Slug)
+	require.Equal("empty", templates[0].Name)
+	require.Equal("packed", templates[1].Slug)
+	require.Equal("Packed", templates[1].Name)
+	require.Equal("service", templates[2].Slug)
+	require.Equal("Service", templates[2].Name)
+	require.Len(templates[2].Variables, 2)
+
+	tmpl, ok, err := Find(dir, "packed")
+	require.NoError(err)
+	require.True(ok)
+	require.Equal(filepath.Join(dir, "packed.tar.gz"), tmpl.Path)
+
+	tmpl, ok, err = Find("", filepath.Join(dir, "service"))
+	require.NoError(err)
+	require.True(ok)
+	require.Equal("service", tmpl.Slug)
+
+	_, ok, err = Find(dir, "getting_started")
+	require.NoError(err)
+	require.False(ok)
+
+	// Extracted tarballs are removed when closed.
+	src, closer, err := Open(filepath.Join(dir, "packed.tar.gz"))
+	require.NoError(err)
+	require.FileExists(filepath.Join(src, "task.sql"))
+	require.NoError(closer.Close())
+	require.NoDirExists(src)
+}
+
+func TestCheckDestination(t *testing.T) {
+	require := require.New(t)
+	dir := t.TempDir()
+	tmpl := Template{Slug: "my_kit", Path: filepath.Join(dir, "my_kit")}
+
+	require.NoError(CheckDestination(tmpl, filepath.Join(dir, "other")))
+	require.NoError(CheckDestination(tmpl, filepath.Join(dir, "my_kit_2")))
+	require.ErrorContains(CheckDestination(tmpl, filepath.Join(dir, "my_kit")), "would overwrite the template")
+	require.ErrorContains(CheckDestination(tmpl, dir), "would overwrite the template")
+}
+
+func TestExtractRejectsEscapingPaths(t *testing.T) {
+	require := require.New(t)
+	path := filepath.Join(t.TempDir(), "evil.tgz")
+	writeTarball(t, path, map[string]string{"../evil.txt": "boom"})
+
+	_, _, err := Open(path)
+	require.Error(err)
+}
+
+func writeTarball(t *testing.T, path string, files map[string]string) {
+	require := require.New(t)
+	f, err := os.Create(path)
+	require.NoError(err)
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for name, contents := range files {
+		require.NoError(tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(contents)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(contents))
+		require.NoError(err)
+	}
+	require.NoError(tw.Close())
+	require.NoError(gz.Close())
+}
@@ -91,32 +91,9 @@ func CopyFromGithubPath(gitPath string) error {
 		if err := CopyDirectoryContents(tempPath, directory); err != nil {
 			return err
 		}
-
-		if fsx.Exists(filepath.Join(directory, "package.json")) {
-			useYarn := ShouldUseYarn(directory)
-			logger.Step("Installing dependencies...")
-
-			if err = InstallDependencies(directory, useYarn); err != nil {
-				logger.Debug(err.Error())
-				if useYarn {
-					return errors.New("error installing dependencies using yarn. Try installing yarn.")
-				}
-				return err
-			}
-			logger.Step("Finished installing dependencies")
-		}
-		if err := CreateDefaultGitignoreFile(filepath.Join(directory, ".gitignore")); err != nil {
+		if err := SetUpDirectory(directory, SetUpDirectoryOpts{}); err != nil {
 			return err
 		}
-		readmePath := filepath.Join(directory, "README.md")
-		if fsx.Exists(readmePath) {
-			logger.Log(logger.Gray(fmt.Sprintf("\nPreviewing %s:", readmePath)))
-			readme, err := os.ReadFile(readmePath)
-			if err != nil {
-				return errors.Wrap(err, "reading README")
-			}
-			logger.Log(string(readme))
-		}
 	} else {
 		cwd, err := os.Getwd()
 		if err != nil {
@@ -139,3 +116,49 @@ func CopyFromGithubPath(gitPath string) error {
 	}
 	return nil
 }
+
+// SetUpDirectoryOpts configures SetUpDirectory.
+type SetUpDirectoryOpts struct {
+	// SkipInstall leaves dependencies to be installed by the user, e.g. so that the directory can
+	// be set up offline.
+	SkipInstall bool
+}
+
+// SetUpDirectory prepares a directory that was copied from a template: it installs
+// dependencies, creates a .gitignore and previews the README, if any.
+func SetUpDirectory(directory string, opts SetUpDirectoryOpts) error {
+	if fsx.Exists(filepath.Join(directory, "package.json")) {
+		useYarn := ShouldUseYarn(directory)
+		if opts.SkipInstall {
+			command := "npm install"
+			if useYarn {
+				command = "yarn install"
+			}
+			logger.Step("Skipped installing dependencies: run `%s` in %s to install them", command, directory)
+		} else {
+			logger.Step("Installing dependencies...")
+
+			if err := InstallDependencies(directory, useYarn); err != nil {
+				logger.Debug(err.Error())
+				if useYarn {
+					return errors.New("error installing dependencies using yarn. Try installing yarn.")
+				}
+				return err
+			}
+			logger.Step("Finished installing dependencies")
+		}
+	}
+	if err := CreateDefaultGitignoreFile(filepath.Join(directory, ".gitignore")); err != nil {
+		return err
+	}
+	readmePath := filepath.Join(directory, "README.md")
+	if fsx.Exists(readmePath) {
+		logger.Log(logger.Gray(fmt.Sprintf("\nPreviewing %s:", readmePath)))
+		readme, err := os.ReadFile(readmePath)
+		if err != nil {
+			return errors.Wrap(err, "reading README")
+		}
+		logger.Log(string(readme))
+	}
+	return nil
+}
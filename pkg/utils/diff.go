@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/airplanedev/cli/pkg/logger"
+	"github.com/hexops/gotextdiff"
+	"github.com/hexops/gotextdiff/myers"
+	"github.com/hexops/gotextdiff/span"
+)
+
+// UnifiedDiff returns a colorized unified diff between two definitions, or nil if they are
+// the same.
+func UnifiedDiff(oldLabel, newLabel, oldStr, newStr string) []string {
+	edits := myers.ComputeEdits(span.URI(oldLabel), oldStr, newStr)
+	edits = gotextdiff.LineEdits(oldStr, edits)
+	diff := fmt.Sprint(gotextdiff.ToUnified(oldLabel, newLabel, oldStr, edits))
+	if diff == "" {
+		return nil
+	}
+
+	// Log deletes in red & additions in green.
+	difflines := strings.Split(diff, "\n")
+	pretty := make([]string, len(difflines))
+	for i, line := range difflines {
+		if strings.HasPrefix(line, "-") {
+			pretty[i] = logger.Red("%s", line)
+		} else if strings.HasPrefix(line, "+") {
+			pretty[i] = logger.Green("%s", line)
+		} else {
+			pretty[i] = line
+		}
+	}
+
+	return pretty
+}
+
+// FieldChange is a change to a single field of a definition. Fields are identified by their
+// path in the definition, e.g. `parameters[0].name`.
+type FieldChange struct {
+	Path string      `json:"path" yaml:"path"`
+	Old  interface{} `json:"old" yaml:"old"`
+	New  interface{} `json:"new" yaml:"new"`
+}
+
+// DiffFields returns the fields that differ between two decoded definitions.
+func DiffFields(path string, old, new interface{}) []FieldChange {
+	oldMap, oldIsMap := old.(map[string]interface{})
+	newMap, newIsMap := new.(map[string]interface{})
+	if oldIsMap && newIsMap {
+		keys := map[string]bool{}
+		for k := range oldMap {
+			keys[k] = true
+		}
+		for k := range newMap {
+			keys[k] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+
+		var changes []FieldChange
+		for _, k := range sorted {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			changes = append(changes, DiffFields(p, oldMap[k], newMap[k])...)
+		}
+		return changes
+	}
+
+	oldList, oldIsList := old.([]interface{})
+	newList, newIsList := new.([]interface{})
+	if oldIsList && newIsList && len(oldList) == len(newList) {
+		var changes []FieldChange
+		for i := range oldList {
+			changes = append(changes, DiffFields(fmt.Sprintf("%s[%d]", path, i), oldList[i], newList[i])...)
+		}
+		return changes
+	}
+
+	if reflect.DeepEqual(old, new) {
+		return nil
+	}
+	return []FieldChange{{Path: path, Old: old, New: new}}
+}
@@ -1,6 +1,22 @@
 package utils
 
+import "fmt"
+
 type ErrorExplained interface {
 	Error() string
 	ExplainError() string
 }
+
+// ExitCodeError causes the CLI to exit with the given code. If Msg is empty, the CLI exits
+// without printing an error.
+type ExitCodeError struct {
+	Code int
+	Msg  string
+}
+
+func (e ExitCodeError) Error() string {
+	if e.Msg == "" {
+		return fmt.Sprintf("exit code %d", e.Code)
+	}
+	return e.Msg
+}